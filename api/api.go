@@ -0,0 +1,690 @@
+// Package api arma el router HTTP de la API de estadísticas: las rutas v1 (en español),
+// las de /api/v2 y la especificación OpenAPI. Los handlers validan la solicitud, leen los
+// datos con los repositorios de repository y responden con los tipos de models.
+package api
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"f1_statshub_system/cache"
+	"f1_statshub_system/i18n"
+	"f1_statshub_system/models"
+	modelsv2 "f1_statshub_system/models/v2"
+	"f1_statshub_system/openapi"
+	"f1_statshub_system/repository"
+	"f1_statshub_system/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorLangKey es la clave del contexto de gin con el idioma de los mensajes de error
+const errorLangKey = "errorLang"
+
+// errorLang devuelve el idioma de los mensajes de error de la solicitud: el que fijó
+// useErrorLang o, si no, el idioma por defecto. No depende de ?lang= ni de
+// Accept-Language, que solo eligen el idioma de las etiquetas de v1.
+func errorLang(c *gin.Context) i18n.Lang {
+	if lang, ok := c.Get(errorLangKey); ok {
+		return lang.(i18n.Lang)
+	}
+	return i18n.Default
+}
+
+// useErrorLang es el middleware que fija el idioma de los mensajes de error de un grupo
+// de rutas
+func useErrorLang(lang i18n.Lang) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(errorLangKey, lang)
+		c.Next()
+	}
+}
+
+// respondError responde con el formato de error común de la API:
+// {"error": {"code": "...", "message": "..."}}. message es la clave del mensaje en el
+// catálogo de i18n, que se formatea con args en el idioma de errorLang.
+func respondError(c *gin.Context, status int, code, message string, args ...interface{}) {
+	c.AbortWithStatusJSON(status, models.ErrorResponse{
+		Error: models.ErrorBody{
+			Code:    code,
+			Message: errorLang(c).T(message, args...),
+		},
+	})
+}
+
+// internalError registra err junto con la ruta y los parámetros de la solicitud y responde
+// 500 con el mensaje message (una clave de i18n), sin mostrarle al cliente el detalle del
+// error
+func internalError(c *gin.Context, err error, message string) {
+	log.Printf("Error en %s %s (ruta %s): %s: %v", c.Request.Method, c.Request.URL.RequestURI(), c.FullPath(), i18n.Default.T(message), err)
+	respondError(c, 500, "internal_error", message)
+}
+
+// parseIDParam lee un parámetro de ruta que debe ser un entero positivo.
+// Si no lo es responde 400 y devuelve false.
+func parseIDParam(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id <= 0 {
+		respondError(c, 400, "invalid_parameter", "api.positive_integer", name)
+		return 0, false
+	}
+	return id, true
+}
+
+// parseIntQuery lee un parámetro de query opcional que debe ser un entero positivo.
+// Si no viene devuelve def; si es inválido responde 400 y devuelve false.
+func parseIntQuery(c *gin.Context, name string, def int) (int, bool) {
+	raw, present := c.GetQuery(name)
+	if !present {
+		return def, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		respondError(c, 400, "invalid_parameter", "api.positive_integer", name)
+		return 0, false
+	}
+	return value, true
+}
+
+// requestLang elige el idioma de las etiquetas generadas en las respuestas de v1: ?lang=
+// si viene, si no Accept-Language, y si no el idioma por defecto. Lo informa en
+// Content-Language. Si ?lang= no es un idioma soportado responde 400 y devuelve false.
+// v2 no genera etiquetas, así que sus handlers no lo usan.
+func requestLang(c *gin.Context) (i18n.Lang, bool) {
+	lang := i18n.FromAcceptLanguage(c.GetHeader("Accept-Language"))
+	if raw, present := c.GetQuery("lang"); present {
+		parsed, ok := i18n.Parse(raw)
+		if !ok {
+			respondError(c, 400, "invalid_parameter", "api.invalid_lang")
+			return "", false
+		}
+		lang = parsed
+	}
+	c.Header("Content-Language", string(lang))
+	return lang, true
+}
+
+// maxPageSize es el máximo que se acepta en ?limit= en los listados
+const maxPageSize = 100
+
+// noLimit es el LIMIT que se usa cuando no viene ?limit=: SQLite y PostgreSQL no
+// comparten una forma de decir sin límite que acepte un placeholder
+const noLimit = math.MaxInt32
+
+// parsePagination lee ?limit= y ?offset= de un listado. Sin ?limit= devuelve noLimit.
+// Si alguno es inválido responde 400 y devuelve false.
+func parsePagination(c *gin.Context) (int, int, bool) {
+	limit, ok := parseIntQuery(c, "limit", 0)
+	if !ok {
+		return 0, 0, false
+	}
+	if limit > maxPageSize {
+		respondError(c, 400, "invalid_parameter", "api.limit_too_large", maxPageSize)
+		return 0, 0, false
+	}
+	if limit == 0 {
+		limit = noLimit
+	}
+
+	offset := 0
+	if raw, present := c.GetQuery("offset"); present {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			respondError(c, 400, "invalid_parameter", "api.invalid_offset")
+			return 0, 0, false
+		}
+		offset = value
+	}
+	return limit, offset, true
+}
+
+// parseSort lee ?sort=campo (ascendente) o ?sort=-campo (descendente) y devuelve el
+// término de ORDER BY. fields mapea los campos aceptados a su columna; def se usa si
+// no viene ?sort=. Si el campo no está permitido responde 400 y devuelve false.
+func parseSort(c *gin.Context, fields map[string]string, def string) (string, bool) {
+	raw := c.DefaultQuery("sort", def)
+	direction := "ASC"
+	if strings.HasPrefix(raw, "-") {
+		direction = "DESC"
+		raw = raw[1:]
+	}
+	column, ok := fields[raw]
+	if !ok {
+		allowed := make([]string, 0, len(fields))
+		for field := range fields {
+			allowed = append(allowed, field)
+		}
+		sort.Strings(allowed)
+		respondError(c, 400, "invalid_parameter", "api.invalid_sort", strings.Join(allowed, ", "))
+		return "", false
+	}
+	return column + " " + direction, true
+}
+
+// Options configura el router
+type Options struct {
+	// DefaultSeason es la temporada de /api/temporada/resumen cuando no viene ?year=
+	DefaultSeason int
+	// Cache guarda las respuestas exitosas (ver cache); nil para no usar cache
+	Cache *cache.Cache
+}
+
+// NewRouter devuelve el router con todas las rutas de la API, que leen los datos de db
+func NewRouter(db *storage.DB, opts Options) *gin.Engine {
+	// Repositorios con las consultas de lectura de los handlers
+	drivers := repository.NewDriverRepo(db)
+	sessions := repository.NewSessionRepo(db)
+	results := repository.NewResultRepo(db)
+	laps := repository.NewLapRepo(db)
+	circuits := repository.NewCircuitRepo(db)
+
+	// loadDrivers lee la página de corredores pedida con los filtros de la query
+	// y deja el total en X-Total-Count. Si falla ya respondió el error.
+	loadDrivers := func(c *gin.Context) ([]models.Driver, bool) {
+		limit, offset, ok := parsePagination(c)
+		if !ok {
+			return nil, false
+		}
+		order, ok := parseSort(c, map[string]string{
+			"driver_number": "driver_number",
+			"first_name":    "first_name",
+			"last_name":     "last_name",
+			"team":          "team_name",
+			"country":       "country_code",
+		}, "driver_number")
+		if !ok {
+			return nil, false
+		}
+
+		// Filtros opcionales; vacío significa sin filtrar
+		corredores, total, err := drivers.List(c.Request.Context(), repository.DriverFilter{
+			Team:    c.Query("team"),
+			Country: c.Query("country"),
+			OrderBy: order,
+			Limit:   limit,
+			Offset:  offset,
+		})
+		if err != nil {
+			internalError(c, err, "api.drivers_query")
+			return nil, false
+		}
+
+		c.Header("X-Total-Count", strconv.Itoa(total))
+		return corredores, true
+	}
+
+	// loadDriverDetail arma el resumen y los resultados de un piloto, sin las etiquetas
+	// de v1 (Race queda vacío)
+	loadDriverDetail := func(c *gin.Context, driverID int) (*models.DriverDetail, bool) {
+		ctx := c.Request.Context()
+
+		exists, err := drivers.Exists(ctx, driverID)
+		if err != nil {
+			internalError(c, err, "api.driver_query")
+			return nil, false
+		}
+		if !exists {
+			respondError(c, 404, "driver_not_found", "api.driver_not_found")
+			return nil, false
+		}
+
+		// 1. Totales del piloto a partir de sus estadísticas por temporada
+		summary, err := results.DriverSummary(ctx, driverID)
+		if err != nil {
+			internalError(c, err, "api.driver_summary")
+			return nil, false
+		}
+
+		// 2. Resultados por carrera en las que el piloto tiene posición final
+		resultados, err := results.DriverResults(ctx, driverID)
+		if err != nil {
+			internalError(c, err, "api.driver_results")
+			return nil, false
+		}
+
+		// 3. Estructura final de respuesta
+		return &models.DriverDetail{
+			DriverID:           strconv.Itoa(driverID),
+			PerformanceSummary: summary,
+			RaceResults:        resultados,
+		}, true
+	}
+
+	// loadRaces lee la página de carreras pedida con los filtros de la query
+	// y deja el total en X-Total-Count
+	loadRaces := func(c *gin.Context) ([]models.Race, bool) {
+		// ?year= es opcional; 0 significa todas las temporadas
+		year, ok := parseIntQuery(c, "year", 0)
+		if !ok {
+			return nil, false
+		}
+		limit, offset, ok := parsePagination(c)
+		if !ok {
+			return nil, false
+		}
+		order, ok := parseSort(c, map[string]string{
+			"date":    "date_start",
+			"year":    "year",
+			"country": "country_name",
+			"circuit": "circuit_short_name",
+		}, "date")
+		if !ok {
+			return nil, false
+		}
+
+		carreras, total, err := sessions.List(c.Request.Context(), repository.RaceFilter{
+			Year:    year,
+			Country: c.Query("country"),
+			Circuit: c.Query("circuit"),
+			OrderBy: order,
+			Limit:   limit,
+			Offset:  offset,
+		})
+		if err != nil {
+			internalError(c, err, "api.races_query")
+			return nil, false
+		}
+
+		c.Header("X-Total-Count", strconv.Itoa(total))
+		return carreras, true
+	}
+
+	// loadRaceDetail arma los resultados y estadísticas de una carrera. Results tiene solo
+	// el podio; el último lugar se devuelve aparte para que cada versión lo presente a su
+	// manera (puede estar vacío si la carrera no tiene posiciones).
+	loadRaceDetail := func(c *gin.Context, sessionID int) (*models.RaceDetail, models.RaceResult, bool) {
+		ctx := c.Request.Context()
+
+		// 1. Info general
+		carrera, err := sessions.Get(ctx, sessionID)
+		if err == repository.ErrNotFound {
+			respondError(c, 404, "race_not_found", "api.race_not_found")
+			return nil, models.RaceResult{}, false
+		}
+		if err != nil {
+			internalError(c, err, "api.race_query")
+			return nil, models.RaceResult{}, false
+		}
+		detalle := models.RaceDetail{
+			RaceID:           strconv.Itoa(sessionID),
+			CountryName:      carrera.CountryName,
+			DateStart:        carrera.DateStart,
+			Year:             carrera.Year,
+			CircuitShortName: carrera.CircuitShortName,
+		}
+
+		// 2. Podio
+		detalle.Results, err = results.Podium(ctx, sessionID)
+		if err != nil {
+			internalError(c, err, "api.podium")
+			return nil, models.RaceResult{}, false
+		}
+
+		// 3. Último lugar (puede no existir si la carrera no tiene posiciones)
+		ultimo, err := results.LastPlace(ctx, sessionID)
+		if err != nil {
+			internalError(c, err, "api.last_place")
+			return nil, models.RaceResult{}, false
+		}
+
+		// 4. Vuelta rápida (la primera en marcarse; si hubo empate se informan los demás pilotos)
+		detalle.FastestLap, err = laps.FastestLap(ctx, sessionID)
+		if err != nil {
+			internalError(c, err, "api.fastest_lap")
+			return nil, models.RaceResult{}, false
+		}
+
+		// 5. Velocidad máxima (puede no existir si la carrera no tiene vueltas)
+		detalle.MaxSpeed, err = laps.MaxSpeed(ctx, sessionID)
+		if err != nil {
+			internalError(c, err, "api.max_speed")
+			return nil, models.RaceResult{}, false
+		}
+
+		// 6. Vueltas lideradas por piloto
+		detalle.LapsLed, err = laps.LapsLed(ctx, sessionID)
+		if err != nil {
+			internalError(c, err, "api.laps_led")
+			return nil, models.RaceResult{}, false
+		}
+
+		// 7. Cambios de líder (vueltas en que el líder difiere del de la vuelta anterior)
+		detalle.LeadChanges, err = laps.LeadChanges(ctx, sessionID)
+		if err != nil {
+			internalError(c, err, "api.lead_changes")
+			return nil, models.RaceResult{}, false
+		}
+
+		// 8. Récord de vuelta del circuito: el vigente y si esta carrera lo rompió
+		// respecto de todas las carreras anteriores ingeridas en el mismo circuito
+		detalle.NewCircuitRecord, err = laps.SetCircuitRecord(ctx, sessionID)
+		if err != nil {
+			internalError(c, err, "api.race_circuit_record")
+			return nil, models.RaceResult{}, false
+		}
+		detalle.CircuitRecord, err = laps.CircuitRecord(ctx, sessionID)
+		if err != nil {
+			internalError(c, err, "api.race_circuit_record")
+			return nil, models.RaceResult{}, false
+		}
+
+		// 🧾 Estructura de respuesta
+		return &detalle, ultimo, true
+	}
+
+	// loadLapChart arma las posiciones vuelta a vuelta de una carrera
+	loadLapChart := func(c *gin.Context, sessionID int) (*models.LapChart, bool) {
+		ctx := c.Request.Context()
+
+		// 1. Verificar que la carrera exista
+		_, err := sessions.Get(ctx, sessionID)
+		if err == repository.ErrNotFound {
+			respondError(c, 404, "race_not_found", "api.race_not_found")
+			return nil, false
+		}
+		if err != nil {
+			internalError(c, err, "api.race_query")
+			return nil, false
+		}
+
+		// 2. Posición y tiempo de cada piloto al final de cada vuelta, agrupados por vuelta
+		vueltas, err := laps.Chart(ctx, sessionID)
+		if err != nil {
+			internalError(c, err, "api.lap_chart")
+			return nil, false
+		}
+
+		return &models.LapChart{RaceID: strconv.Itoa(sessionID), Laps: vueltas}, true
+	}
+
+	// loadSeasonSummary arma los rankings de una temporada
+	loadSeasonSummary := func(c *gin.Context, year int) (*models.SeasonSummary, bool) {
+		ctx := c.Request.Context()
+		// Los rankings empiezan vacíos para que una temporada sin datos responda [] y no null
+		summary := models.SeasonSummary{
+			Season:          year,
+			Top3Winners:     []models.SeasonWinner{},
+			Top3FastestLaps: []models.SeasonFastestLaps{},
+			Top3Podiums:     []models.SeasonPodiums{},
+			Top3LapsLed:     []models.SeasonLapsLed{},
+		}
+
+		// 1. Top 3 ganadores
+		winners, err := results.SeasonLeaders(ctx, year, repository.SeasonWins, 3)
+		if err != nil {
+			internalError(c, err, "api.season_winners")
+			return nil, false
+		}
+		for _, w := range winners {
+			summary.Top3Winners = append(summary.Top3Winners, models.SeasonWinner{RankedDriver: w.RankedDriver, Wins: w.Value})
+		}
+
+		// 2. Top 3 vueltas rápidas (en caso de empate en una carrera cuenta para todos los pilotos empatados)
+		fastest, err := results.SeasonLeaders(ctx, year, repository.SeasonFastestLaps, 3)
+		if err != nil {
+			internalError(c, err, "api.season_fastest_laps")
+			return nil, false
+		}
+		for _, f := range fastest {
+			summary.Top3FastestLaps = append(summary.Top3FastestLaps, models.SeasonFastestLaps{RankedDriver: f.RankedDriver, FastestLaps: f.Value})
+		}
+
+		// 3. Top 3 en podios (corredores con más posiciones <= 3)
+		podiums, err := results.SeasonLeaders(ctx, year, repository.SeasonPodiums, 3)
+		if err != nil {
+			internalError(c, err, "api.season_podiums")
+			return nil, false
+		}
+		for _, p := range podiums {
+			summary.Top3Podiums = append(summary.Top3Podiums, models.SeasonPodiums{RankedDriver: p.RankedDriver, Podiums: p.Value})
+		}
+
+		// 4. Top 3 en vueltas lideradas durante la temporada
+		lapsLed, err := results.SeasonLeaders(ctx, year, repository.SeasonLapsLed, 3)
+		if err != nil {
+			internalError(c, err, "api.season_laps_led")
+			return nil, false
+		}
+		for _, l := range lapsLed {
+			summary.Top3LapsLed = append(summary.Top3LapsLed, models.SeasonLapsLed{RankedDriver: l.RankedDriver, LapsLed: l.Value})
+		}
+
+		// 5. Respuesta final
+		return &summary, true
+	}
+
+	// loadCircuits lee todos los circuitos
+	loadCircuits := func(c *gin.Context) ([]models.Circuit, bool) {
+		circuitos, err := circuits.List(c.Request.Context())
+		if err != nil {
+			internalError(c, err, "api.circuits_query")
+			return nil, false
+		}
+		return circuitos, true
+	}
+
+	// loadCircuitDetail arma el historial y el récord de vuelta de un circuito
+	loadCircuitDetail := func(c *gin.Context, circuitKey int) (*models.CircuitDetail, bool) {
+		ctx := c.Request.Context()
+
+		// 1. Info general del circuito
+		circuito, err := circuits.Get(ctx, circuitKey)
+		if err == repository.ErrNotFound {
+			respondError(c, 404, "circuit_not_found", "api.circuit_not_found")
+			return nil, false
+		}
+		if err != nil {
+			internalError(c, err, "api.circuit_query")
+			return nil, false
+		}
+		detalle := models.CircuitDetail{
+			CircuitKey:       circuitKey,
+			CircuitShortName: circuito.CircuitShortName,
+			Location:         circuito.Location,
+			CountryName:      circuito.CountryName,
+			LapLengthKm:      circuito.LapLengthKm,
+		}
+
+		// 2. Carreras disputadas en el circuito, con ganador, vuelta rápida y velocidad promedio
+		detalle.Races, err = circuits.Races(ctx, circuito)
+		if err != nil {
+			internalError(c, err, "api.circuit_races")
+			return nil, false
+		}
+		for _, carrera := range detalle.Races {
+			if carrera.Winner != "" {
+				detalle.PastWinners = append(detalle.PastWinners, models.CircuitWinner{
+					Year:   carrera.Year,
+					Driver: carrera.Winner,
+					Team:   carrera.Team,
+				})
+			}
+		}
+
+		// 3. Récord de vuelta en el circuito (todas las temporadas ingeridas)
+		detalle.LapRecord, err = circuits.LapRecord(ctx, circuito)
+		if err != nil {
+			internalError(c, err, "api.circuit_lap_record")
+			return nil, false
+		}
+
+		// 4. Estructura final de respuesta
+		return &detalle, true
+	}
+
+	r := gin.Default()
+	if opts.Cache != nil {
+		r.Use(opts.Cache.Middleware())
+	}
+
+	// v1: rutas originales en español, se mantienen por compatibilidad
+	r.GET("/api/corredor", func(c *gin.Context) {
+		if corredores, ok := loadDrivers(c); ok {
+			c.JSON(200, corredores)
+		}
+	})
+
+	r.GET("/api/corredor/detalle/:id", func(c *gin.Context) {
+		driverID, ok := parseIDParam(c, "id")
+		if !ok {
+			return
+		}
+		lang, ok := requestLang(c)
+		if !ok {
+			return
+		}
+		if detalle, ok := loadDriverDetail(c, driverID); ok {
+			for i := range detalle.RaceResults {
+				detalle.RaceResults[i].Race = lang.T("label.race_name", detalle.RaceResults[i].CountryName)
+			}
+			c.JSON(200, detalle)
+		}
+	})
+
+	r.GET("/api/carrera", func(c *gin.Context) {
+		if carreras, ok := loadRaces(c); ok {
+			c.JSON(200, carreras)
+		}
+	})
+
+	r.GET("/api/carrera/detalle/:id", func(c *gin.Context) {
+		sessionID, ok := parseIDParam(c, "id")
+		if !ok {
+			return
+		}
+		lang, ok := requestLang(c)
+		if !ok {
+			return
+		}
+		if detalle, ultimo, ok := loadRaceDetail(c, sessionID); ok {
+			// En v1 el último lugar va al final de results con la etiqueta "Último"
+			ultimo.Position.Label = lang.T("label.last_place")
+			detalle.Results = append(detalle.Results, ultimo)
+			c.JSON(200, detalle)
+		}
+	})
+
+	r.GET("/api/carrera/detalle/:id/vueltas", func(c *gin.Context) {
+		sessionID, ok := parseIDParam(c, "id")
+		if !ok {
+			return
+		}
+		if chart, ok := loadLapChart(c, sessionID); ok {
+			c.JSON(200, chart)
+		}
+	})
+
+	r.GET("/api/temporada/resumen", func(c *gin.Context) {
+		year, ok := parseIntQuery(c, "year", opts.DefaultSeason)
+		if !ok {
+			return
+		}
+		if resumen, ok := loadSeasonSummary(c, year); ok {
+			c.JSON(200, resumen)
+		}
+	})
+
+	r.GET("/api/circuito", func(c *gin.Context) {
+		if circuitos, ok := loadCircuits(c); ok {
+			c.JSON(200, circuitos)
+		}
+	})
+
+	r.GET("/api/circuito/:key", func(c *gin.Context) {
+		circuitKey, ok := parseIDParam(c, "key")
+		if !ok {
+			return
+		}
+		if detalle, ok := loadCircuitDetail(c, circuitKey); ok {
+			c.JSON(200, detalle)
+		}
+	})
+
+	// v2: recursos, campos y mensajes de error en inglés, sin textos generados
+	v2 := r.Group("/api/v2", useErrorLang(i18n.English))
+
+	v2.GET("/drivers", func(c *gin.Context) {
+		if drivers, ok := loadDrivers(c); ok {
+			c.JSON(200, drivers)
+		}
+	})
+
+	v2.GET("/drivers/:number", func(c *gin.Context) {
+		driverNumber, ok := parseIDParam(c, "number")
+		if !ok {
+			return
+		}
+		if detail, ok := loadDriverDetail(c, driverNumber); ok {
+			c.JSON(200, modelsv2.NewDriverDetail(driverNumber, *detail))
+		}
+	})
+
+	v2.GET("/sessions", func(c *gin.Context) {
+		if sessions, ok := loadRaces(c); ok {
+			c.JSON(200, sessions)
+		}
+	})
+
+	v2.GET("/sessions/:key", func(c *gin.Context) {
+		sessionKey, ok := parseIDParam(c, "key")
+		if !ok {
+			return
+		}
+		if detail, lastPlace, ok := loadRaceDetail(c, sessionKey); ok {
+			c.JSON(200, modelsv2.NewSessionDetail(sessionKey, *detail, lastPlace))
+		}
+	})
+
+	v2.GET("/sessions/:key/laps", func(c *gin.Context) {
+		sessionKey, ok := parseIDParam(c, "key")
+		if !ok {
+			return
+		}
+		if chart, ok := loadLapChart(c, sessionKey); ok {
+			c.JSON(200, modelsv2.NewLapChart(sessionKey, *chart))
+		}
+	})
+
+	v2.GET("/seasons/:year", func(c *gin.Context) {
+		year, ok := parseIDParam(c, "year")
+		if !ok {
+			return
+		}
+		if summary, ok := loadSeasonSummary(c, year); ok {
+			c.JSON(200, modelsv2.NewSeasonSummary(*summary))
+		}
+	})
+
+	v2.GET("/circuits", func(c *gin.Context) {
+		if circuits, ok := loadCircuits(c); ok {
+			c.JSON(200, circuits)
+		}
+	})
+
+	v2.GET("/circuits/:key", func(c *gin.Context) {
+		circuitKey, ok := parseIDParam(c, "key")
+		if !ok {
+			return
+		}
+		if detail, ok := loadCircuitDetail(c, circuitKey); ok {
+			c.JSON(200, detail)
+		}
+	})
+
+	r.GET("/api/openapi.json", func(c *gin.Context) {
+		c.Data(200, "application/json; charset=utf-8", openapi.Spec)
+	})
+
+	r.NoRoute(func(c *gin.Context) {
+		// NoRoute no pasa por el middleware del grupo v2
+		if strings.HasPrefix(c.Request.URL.Path, "/api/v2/") {
+			c.Set(errorLangKey, i18n.English)
+		}
+		respondError(c, 404, "route_not_found", "api.route_not_found")
+	})
+
+	return r
+}
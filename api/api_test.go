@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"f1_statshub_system/models"
+	modelsv2 "f1_statshub_system/models/v2"
+)
+
+// TestLanguage comprueba que ?lang= y Accept-Language solo afecten a v1: v2 no valida
+// ?lang=, no informa Content-Language y responde los errores en inglés
+func TestLanguage(t *testing.T) {
+	router := newTestRouter(t)
+
+	tests := []struct {
+		name            string
+		url             string
+		header          map[string]string
+		status          int
+		contentLanguage string
+		errorMessage    string
+	}{
+		{name: "v1 idioma por defecto", url: "/api/corredor/detalle/1", status: 200, contentLanguage: "es"},
+		{name: "v1 ?lang=en", url: "/api/carrera/detalle/9472?lang=en", status: 200, contentLanguage: "en"},
+		{name: "v1 Accept-Language", url: "/api/carrera/detalle/9472", header: map[string]string{"Accept-Language": "en-US,en;q=0.9"}, status: 200, contentLanguage: "en"},
+		{name: "v1 ?lang= inválido", url: "/api/corredor/detalle/1?lang=fr", status: 400, contentLanguage: "", errorMessage: "El parámetro lang debe ser es o en"},
+		{name: "v1 error en español con ?lang=en", url: "/api/corredor/detalle/99?lang=en", status: 404, contentLanguage: "en", errorMessage: "Corredor no encontrado"},
+		{name: "v1 ruta inexistente", url: "/api/corredores", status: 404, errorMessage: "Ruta no encontrada"},
+		{name: "v2 ignora ?lang= inválido", url: "/api/v2/drivers/1?lang=fr", status: 200},
+		{name: "v2 ignora ?lang=", url: "/api/v2/sessions/9472?lang=es", status: 200},
+		{name: "v2 ignora Accept-Language", url: "/api/v2/sessions/9472", header: map[string]string{"Accept-Language": "es"}, status: 200},
+		{name: "v2 404 en inglés", url: "/api/v2/drivers/99?lang=es", status: 404, errorMessage: "Driver not found"},
+		{name: "v2 400 en inglés", url: "/api/v2/sessions?limit=500", status: 400, errorMessage: "The limit parameter cannot be greater than 100"},
+		{name: "v2 parámetro de ruta en inglés", url: "/api/v2/circuits/x", header: map[string]string{"Accept-Language": "es"}, status: 400, errorMessage: "The key parameter must be a positive integer"},
+		{name: "v2 ruta inexistente", url: "/api/v2/teams", status: 404, errorMessage: "Route not found"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(router, tc.url, tc.header)
+			if w.Code != tc.status {
+				t.Fatalf("código %d, se esperaba %d: %s", w.Code, tc.status, w.Body)
+			}
+			if got := w.Header().Get("Content-Language"); got != tc.contentLanguage {
+				t.Errorf("Content-Language %q, se esperaba %q", got, tc.contentLanguage)
+			}
+			if tc.errorMessage == "" {
+				return
+			}
+			var body models.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("el cuerpo no es un error: %v", err)
+			}
+			if body.Error.Message != tc.errorMessage {
+				t.Errorf("mensaje %q, se esperaba %q", body.Error.Message, tc.errorMessage)
+			}
+		})
+	}
+}
+
+// TestRaceDetailLastPlace comprueba que v1 agregue el último lugar a results con la
+// etiqueta del idioma pedido y que v2 lo informe aparte del podio
+func TestRaceDetailLastPlace(t *testing.T) {
+	router := newTestRouter(t)
+
+	var v1 models.RaceDetail
+	w := serve(router, "/api/carrera/detalle/9472?lang=en", nil)
+	if err := json.Unmarshal(w.Body.Bytes(), &v1); err != nil {
+		t.Fatalf("respuesta v1 inválida: %v: %s", err, w.Body)
+	}
+	if len(v1.Results) != 4 {
+		t.Fatalf("v1 tiene %d resultados, se esperaban 4 (podio y último)", len(v1.Results))
+	}
+	if last := v1.Results[3]; last.Position.Label != "Last" || last.Driver != "Charles Leclerc" {
+		t.Errorf("último lugar v1 = %+v, se esperaba Charles Leclerc con la etiqueta Last", last)
+	}
+
+	var v2 modelsv2.SessionDetail
+	w = serve(router, "/api/v2/sessions/9472", nil)
+	if err := json.Unmarshal(w.Body.Bytes(), &v2); err != nil {
+		t.Fatalf("respuesta v2 inválida: %v: %s", err, w.Body)
+	}
+	if len(v2.Podium) != 3 {
+		t.Errorf("v2 tiene %d pilotos en el podio, se esperaban 3", len(v2.Podium))
+	}
+	if v2.LastPlace == nil || v2.LastPlace.Driver != "Charles Leclerc" || v2.LastPlace.Position != 3 {
+		t.Errorf("último lugar v2 = %+v, se esperaba Charles Leclerc en la posición 3", v2.LastPlace)
+	}
+}
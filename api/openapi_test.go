@@ -0,0 +1,315 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"f1_statshub_system/openapi"
+	"f1_statshub_system/storage/storagetest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// specRequest es una solicitud a una ruta documentada y el código que debe responder
+type specRequest struct {
+	path   string // ruta como figura en la especificación
+	url    string
+	header map[string]string
+	status int
+}
+
+// specRequests cubre cada ruta de la especificación con al menos una respuesta 200 sobre
+// los datos de storagetest.Seed, además de los 400 y 404 documentados
+var specRequests = []specRequest{
+	{path: "/api/corredor", url: "/api/corredor", status: 200},
+	{path: "/api/corredor", url: "/api/corredor?team=Ferrari&sort=-last_name&limit=1&offset=0", status: 200},
+	{path: "/api/corredor", url: "/api/corredor?limit=101", status: 400},
+	{path: "/api/corredor", url: "/api/corredor?sort=edad", status: 400},
+	{path: "/api/corredor/detalle/{id}", url: "/api/corredor/detalle/1", status: 200},
+	{path: "/api/corredor/detalle/{id}", url: "/api/corredor/detalle/4?lang=en", status: 200},
+	{path: "/api/corredor/detalle/{id}", url: "/api/corredor/detalle/16", header: map[string]string{"Accept-Language": "en-US"}, status: 200},
+	{path: "/api/corredor/detalle/{id}", url: "/api/corredor/detalle/99", status: 404},
+	{path: "/api/corredor/detalle/{id}", url: "/api/corredor/detalle/abc", status: 400},
+	{path: "/api/corredor/detalle/{id}", url: "/api/corredor/detalle/1?lang=fr", status: 400},
+	{path: "/api/carrera", url: "/api/carrera", status: 200},
+	{path: "/api/carrera", url: "/api/carrera?year=2024&circuit=Sakhir&sort=-date&limit=5", status: 200},
+	{path: "/api/carrera", url: "/api/carrera?year=0", status: 400},
+	{path: "/api/carrera/detalle/{id}", url: "/api/carrera/detalle/9472", status: 200},
+	{path: "/api/carrera/detalle/{id}", url: "/api/carrera/detalle/9480?lang=en", status: 200},
+	{path: "/api/carrera/detalle/{id}", url: "/api/carrera/detalle/1", status: 404},
+	{path: "/api/carrera/detalle/{id}", url: "/api/carrera/detalle/-1", status: 400},
+	{path: "/api/carrera/detalle/{id}/vueltas", url: "/api/carrera/detalle/9472/vueltas", status: 200},
+	{path: "/api/carrera/detalle/{id}/vueltas", url: "/api/carrera/detalle/9480/vueltas", status: 200},
+	{path: "/api/carrera/detalle/{id}/vueltas", url: "/api/carrera/detalle/1/vueltas", status: 404},
+	{path: "/api/carrera/detalle/{id}/vueltas", url: "/api/carrera/detalle/x/vueltas", status: 400},
+	{path: "/api/temporada/resumen", url: "/api/temporada/resumen", status: 200},
+	{path: "/api/temporada/resumen", url: "/api/temporada/resumen?year=2023", status: 200},
+	{path: "/api/temporada/resumen", url: "/api/temporada/resumen?year=1990", status: 200},
+	{path: "/api/temporada/resumen", url: "/api/temporada/resumen?year=dos", status: 400},
+	{path: "/api/circuito", url: "/api/circuito", status: 200},
+	{path: "/api/circuito/{key}", url: "/api/circuito/63", status: 200},
+	{path: "/api/circuito/{key}", url: "/api/circuito/149", status: 200},
+	{path: "/api/circuito/{key}", url: "/api/circuito/7", status: 404},
+	{path: "/api/circuito/{key}", url: "/api/circuito/0", status: 400},
+
+	{path: "/api/v2/drivers", url: "/api/v2/drivers", status: 200},
+	{path: "/api/v2/drivers", url: "/api/v2/drivers?country=GBR&sort=-driver_number", status: 200},
+	{path: "/api/v2/drivers", url: "/api/v2/drivers?offset=-1", status: 400},
+	{path: "/api/v2/drivers/{number}", url: "/api/v2/drivers/1", status: 200},
+	{path: "/api/v2/drivers/{number}", url: "/api/v2/drivers/4", status: 200},
+	{path: "/api/v2/drivers/{number}", url: "/api/v2/drivers/99", status: 404},
+	{path: "/api/v2/drivers/{number}", url: "/api/v2/drivers/uno", status: 400},
+	{path: "/api/v2/sessions", url: "/api/v2/sessions", status: 200},
+	{path: "/api/v2/sessions", url: "/api/v2/sessions?country=Bahrain&sort=-date", status: 200},
+	{path: "/api/v2/sessions", url: "/api/v2/sessions?sort=winner", status: 400},
+	{path: "/api/v2/sessions/{key}", url: "/api/v2/sessions/7953", status: 200},
+	{path: "/api/v2/sessions/{key}", url: "/api/v2/sessions/9472", status: 200},
+	{path: "/api/v2/sessions/{key}", url: "/api/v2/sessions/1", status: 404},
+	{path: "/api/v2/sessions/{key}", url: "/api/v2/sessions/0", status: 400},
+	{path: "/api/v2/sessions/{key}/laps", url: "/api/v2/sessions/9472/laps", status: 200},
+	{path: "/api/v2/sessions/{key}/laps", url: "/api/v2/sessions/1/laps", status: 404},
+	{path: "/api/v2/sessions/{key}/laps", url: "/api/v2/sessions/x/laps", status: 400},
+	{path: "/api/v2/seasons/{year}", url: "/api/v2/seasons/2024", status: 200},
+	{path: "/api/v2/seasons/{year}", url: "/api/v2/seasons/1990", status: 200},
+	{path: "/api/v2/seasons/{year}", url: "/api/v2/seasons/actual", status: 400},
+	{path: "/api/v2/circuits", url: "/api/v2/circuits", status: 200},
+	{path: "/api/v2/circuits/{key}", url: "/api/v2/circuits/63", status: 200},
+	{path: "/api/v2/circuits/{key}", url: "/api/v2/circuits/149", status: 200},
+	{path: "/api/v2/circuits/{key}", url: "/api/v2/circuits/7", status: 404},
+	{path: "/api/v2/circuits/{key}", url: "/api/v2/circuits/-3", status: 400},
+}
+
+// spec es el subconjunto de OpenAPI que usan los tests
+type spec struct {
+	Paths map[string]map[string]struct {
+		Responses map[string]struct {
+			Content map[string]struct {
+				Schema *schema `json:"schema"`
+			} `json:"content"`
+		} `json:"responses"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// schema es un esquema de OpenAPI con las palabras clave que usa openapi.json
+type schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Nullable   bool               `json:"nullable"`
+	Required   []string           `json:"required"`
+	Properties map[string]*schema `json:"properties"`
+	Items      *schema            `json:"items"`
+	OneOf      []*schema          `json:"oneOf"`
+}
+
+func loadSpec(t *testing.T) *spec {
+	t.Helper()
+	var s spec
+	if err := json.Unmarshal(openapi.Spec, &s); err != nil {
+		t.Fatalf("openapi.json no es válido: %v", err)
+	}
+	return &s
+}
+
+// validate devuelve las diferencias entre value (decodificado con UseNumber) y el esquema
+// s. Las propiedades que el esquema no declara también son errores, para que un campo
+// nuevo en models no pase sin documentar.
+func (sp *spec) validate(s *schema, value interface{}, at string) []string {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		ref, ok := sp.Components.Schemas[name]
+		if !ok {
+			return []string{fmt.Sprintf("%s: $ref desconocido %s", at, s.Ref)}
+		}
+		return sp.validate(ref, value, at)
+	}
+	if value == nil {
+		if s.Nullable {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: null en un campo no nullable", at)}
+	}
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, option := range s.OneOf {
+			if len(sp.validate(option, value, at)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return []string{fmt.Sprintf("%s: %v cumple %d esquemas de oneOf", at, value, matches)}
+		}
+		return nil
+	}
+
+	var errs []string
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: se esperaba un objeto y llegó %T", at, value)}
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: falta la propiedad requerida %s", at, name))
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("%s: propiedad %s no documentada", at, name))
+				continue
+			}
+			errs = append(errs, sp.validate(property, object[name], at+"."+name)...)
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: se esperaba un arreglo y llegó %T", at, value)}
+		}
+		for i, item := range array {
+			errs = append(errs, sp.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: se esperaba un string y llegó %T", at, value)}
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %q no es date-time", at, text))
+			}
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return []string{fmt.Sprintf("%s: se esperaba un entero y llegó %T", at, value)}
+		}
+		if _, err := strconv.ParseInt(number.String(), 10, 64); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s no es entero", at, number))
+		}
+	case "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return []string{fmt.Sprintf("%s: se esperaba un número y llegó %T", at, value)}
+		}
+		if f, err := number.Float64(); err != nil || math.IsInf(f, 0) {
+			errs = append(errs, fmt.Sprintf("%s: %s no es número", at, number))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: se esperaba un booleano y llegó %T", at, value)}
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("%s: tipo %q no soportado por el test", at, s.Type))
+	}
+	return errs
+}
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	return NewRouter(storagetest.OpenSeeded(t), Options{DefaultSeason: 2024})
+}
+
+func serve(router http.Handler, url string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", url, nil)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestResponsesMatchSpec llama cada ruta documentada y valida el cuerpo de la respuesta
+// contra el esquema de la especificación para ese código
+func TestResponsesMatchSpec(t *testing.T) {
+	sp := loadSpec(t)
+	router := newTestRouter(t)
+
+	for _, tc := range specRequests {
+		t.Run(tc.url, func(t *testing.T) {
+			operation, ok := sp.Paths[tc.path]["get"]
+			if !ok {
+				t.Fatalf("la especificación no documenta GET %s", tc.path)
+			}
+			response, ok := operation.Responses[strconv.Itoa(tc.status)]
+			if !ok {
+				t.Fatalf("la especificación no documenta %d para GET %s", tc.status, tc.path)
+			}
+
+			w := serve(router, tc.url, tc.header)
+			if w.Code != tc.status {
+				t.Fatalf("código %d, se esperaba %d: %s", w.Code, tc.status, w.Body)
+			}
+			content, ok := response.Content["application/json"]
+			if !ok || content.Schema == nil {
+				t.Fatalf("la respuesta %d de GET %s no tiene esquema application/json", tc.status, tc.path)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+				t.Errorf("Content-Type %q, se esperaba application/json", got)
+			}
+
+			decoder := json.NewDecoder(bytes.NewReader(w.Body.Bytes()))
+			decoder.UseNumber()
+			var body interface{}
+			if err := decoder.Decode(&body); err != nil {
+				t.Fatalf("el cuerpo no es JSON: %v", err)
+			}
+			for _, err := range sp.validate(content.Schema, body, "body") {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestSpecCoversRoutes comprueba que cada ruta de la especificación tenga un caso 200 en
+// specRequests y que cada ruta del router esté documentada
+func TestSpecCoversRoutes(t *testing.T) {
+	sp := loadSpec(t)
+	router := newTestRouter(t)
+
+	tested := map[string]bool{}
+	for _, tc := range specRequests {
+		if tc.status == 200 {
+			tested[tc.path] = true
+		}
+	}
+	for path := range sp.Paths {
+		if !tested[path] {
+			t.Errorf("GET %s está documentada pero no tiene un caso 200 en specRequests", path)
+		}
+	}
+
+	param := regexp.MustCompile(`:(\w+)`)
+	for _, route := range router.Routes() {
+		if route.Path == "/api/openapi.json" {
+			continue
+		}
+		path := param.ReplaceAllString(route.Path, "{$1}")
+		if _, ok := sp.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s no está documentada en openapi.json", route.Method, path)
+		}
+	}
+}
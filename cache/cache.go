@@ -0,0 +1,166 @@
+// Package cache guarda en memoria las respuestas exitosas de la API para no recalcular
+// las estadísticas en cada solicitud. Las entradas se identifican por ruta, parámetros
+// de query e idioma pedido. Cada una vence a los ttl de guardada: es la única forma en
+// que el servidor toma los datos que escriba otro proceso sobre la misma base (rebuild-stats,
+// una ingesta posterior u otra instancia), porque el servidor no escribe en la base mientras
+// responde y su propia ingesta termina antes de crear el cache. También responde
+// ETag/If-None-Match y Cache-Control.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxEntries es la cantidad de respuestas que se guardan antes de vaciar el cache
+const DefaultMaxEntries = 1000
+
+// storedHeaders son los headers de la respuesta original que se repiten en cada acierto
+var storedHeaders = []string{"Content-Type", "Content-Language", "X-Total-Count"}
+
+type entry struct {
+	body    []byte
+	etag    string
+	header  http.Header
+	expires time.Time
+}
+
+// Cache es un cache de respuestas seguro para uso concurrente
+type Cache struct {
+	mu         sync.RWMutex
+	entries    map[string]entry
+	maxAge     time.Duration
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+}
+
+// New crea un cache que guarda cada respuesta durante ttl y cuyas respuestas los
+// clientes pueden reutilizar durante maxAge sin volver a preguntar (Cache-Control:
+// max-age). Con ttl <= 0 las respuestas no vencen.
+func New(maxAge, ttl time.Duration) *Cache {
+	return &Cache{
+		entries:    make(map[string]entry),
+		maxAge:     maxAge,
+		ttl:        ttl,
+		maxEntries: DefaultMaxEntries,
+		now:        time.Now,
+	}
+}
+
+// Middleware sirve las solicitudes GET desde el cache y guarda las respuestas 200 de
+// los handlers. Las respuestas de error nunca se guardan.
+func (c *Cache) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.Method != http.MethodGet {
+			ctx.Next()
+			return
+		}
+		key := ctx.Request.URL.Path + "?" + ctx.Request.URL.Query().Encode() + "|" + ctx.GetHeader("Accept-Language")
+
+		c.mu.RLock()
+		cached, hit := c.entries[key]
+		c.mu.RUnlock()
+		// Una entrada vencida se vuelve a calcular y se reemplaza al guardar la nueva
+		if hit && c.ttl > 0 && !c.now().Before(cached.expires) {
+			hit = false
+		}
+
+		if hit {
+			for name, values := range cached.header {
+				ctx.Writer.Header()[name] = values
+			}
+			c.respond(ctx, cached)
+			ctx.Abort()
+			return
+		}
+
+		// Guardar la respuesta del handler en un buffer para poder calcular el ETag
+		// antes de enviar los headers
+		original := ctx.Writer
+		buffer := &bufferedWriter{ResponseWriter: original}
+		ctx.Writer = buffer
+		ctx.Next()
+		ctx.Writer = original
+
+		if buffer.Status() != http.StatusOK {
+			original.Write(buffer.body.Bytes())
+			return
+		}
+
+		stored := entry{
+			body:    buffer.body.Bytes(),
+			etag:    etagFor(buffer.body.Bytes()),
+			header:  http.Header{},
+			expires: c.now().Add(c.ttl),
+		}
+		for _, name := range storedHeaders {
+			if values := original.Header().Values(name); len(values) > 0 {
+				stored.header[name] = values
+			}
+		}
+
+		c.mu.Lock()
+		if len(c.entries) >= c.maxEntries {
+			c.entries = make(map[string]entry)
+		}
+		c.entries[key] = stored
+		c.mu.Unlock()
+
+		c.respond(ctx, stored)
+	}
+}
+
+// respond envía una respuesta guardada, o 304 si el cliente ya tiene esa versión
+func (c *Cache) respond(ctx *gin.Context, e entry) {
+	header := ctx.Writer.Header()
+	header.Set("ETag", e.etag)
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(c.maxAge.Seconds())))
+	header.Add("Vary", "Accept-Language")
+
+	if matchesETag(ctx.GetHeader("If-None-Match"), e.etag) {
+		ctx.Writer.WriteHeader(http.StatusNotModified)
+		ctx.Writer.WriteHeaderNow()
+		return
+	}
+	ctx.Writer.WriteHeader(http.StatusOK)
+	ctx.Writer.Write(e.body)
+}
+
+// matchesETag indica si el header If-None-Match (una lista de ETags o "*") incluye etag
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func etagFor(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// bufferedWriter retiene el cuerpo de la respuesta en vez de enviarlo
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
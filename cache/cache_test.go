@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testServer devuelve un router con el cache y un handler que responde cuántas veces se
+// ejecutó, para distinguir las respuestas del cache de las recalculadas
+func testServer(c *Cache) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+
+	calls := 0
+	r := gin.New()
+	r.Use(c.Middleware())
+	r.GET("/ok", func(ctx *gin.Context) {
+		calls++
+		ctx.Header("X-Total-Count", "7")
+		ctx.String(200, strconv.Itoa(calls))
+	})
+	r.GET("/error", func(ctx *gin.Context) {
+		calls++
+		ctx.String(500, strconv.Itoa(calls))
+	})
+	return r, &calls
+}
+
+func get(r http.Handler, url string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", url, nil)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	now := time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC)
+	c := New(30*time.Second, time.Minute)
+	c.now = func() time.Time { return now }
+	r, calls := testServer(c)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		url     string
+		header  map[string]string
+		status  int
+		body    string
+	}{
+		{name: "primera solicitud", url: "/ok", status: 200, body: "1"},
+		{name: "acierto", advance: 59 * time.Second, url: "/ok", status: 200, body: "1"},
+		{name: "otra query", url: "/ok?x=1", status: 200, body: "2"},
+		{name: "otro idioma", url: "/ok", header: map[string]string{"Accept-Language": "en"}, status: 200, body: "3"},
+		{name: "vencida", advance: time.Second, url: "/ok", status: 200, body: "4"},
+		{name: "acierto de la nueva", advance: 30 * time.Second, url: "/ok", status: 200, body: "4"},
+		{name: "error no se guarda", url: "/error", status: 500, body: "5"},
+		{name: "error otra vez", url: "/error", status: 500, body: "6"},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		w := get(r, step.url, step.header)
+		if w.Code != step.status || w.Body.String() != step.body {
+			t.Fatalf("%s: %d %q, se esperaba %d %q", step.name, w.Code, w.Body, step.status, step.body)
+		}
+		if step.status == 200 && w.Header().Get("X-Total-Count") != "7" {
+			t.Errorf("%s: no se repitió X-Total-Count", step.name)
+		}
+	}
+	if *calls != 6 {
+		t.Errorf("el handler se ejecutó %d veces, se esperaban 6", *calls)
+	}
+}
+
+func TestWithoutTTL(t *testing.T) {
+	now := time.Now()
+	c := New(30*time.Second, 0)
+	c.now = func() time.Time { return now }
+	r, _ := testServer(c)
+
+	get(r, "/ok", nil)
+	now = now.Add(24 * time.Hour)
+	if body := get(r, "/ok", nil).Body.String(); body != "1" {
+		t.Errorf("sin ttl la respuesta venció: %q", body)
+	}
+}
+
+func TestETag(t *testing.T) {
+	c := New(30*time.Second, time.Minute)
+	r, _ := testServer(c)
+
+	first := get(r, "/ok", nil)
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("la respuesta no tiene ETag")
+	}
+	if got := first.Header().Get("Cache-Control"); got != "public, max-age=30" {
+		t.Errorf("Cache-Control %q", got)
+	}
+
+	tests := []struct {
+		ifNoneMatch string
+		status      int
+	}{
+		{etag, 304},
+		{`"otro", ` + etag, 304},
+		{"W/" + etag, 304},
+		{"*", 304},
+		{`"otro"`, 200},
+	}
+	for _, tc := range tests {
+		w := get(r, "/ok", map[string]string{"If-None-Match": tc.ifNoneMatch})
+		if w.Code != tc.status {
+			t.Errorf("If-None-Match %s: código %d, se esperaba %d", tc.ifNoneMatch, w.Code, tc.status)
+		}
+		if tc.status == 304 && w.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: 304 con cuerpo %q", tc.ifNoneMatch, w.Body)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Package client es un cliente Go para la API de estadísticas de F1 que expone server.go.
+// Lo usan cliente.go y cualquier otro servicio que necesite consumir la API.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"f1_statshub_system/models"
+)
+
+// DefaultBaseURL es la dirección en la que server.go escucha por defecto
+const DefaultBaseURL = "http://localhost:8080"
+
+// DefaultTimeout es el tiempo máximo de cada solicitud si no se configura otro HTTPClient
+const DefaultTimeout = 10 * time.Second
+
+// Client consume la API de estadísticas. BaseURL, HTTPClient y Lang se pueden modificar
+// después de crearlo con New. Si Lang no está vacío se envía como Accept-Language para
+// que las etiquetas que genera el servidor vengan en ese idioma.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Lang       string
+}
+
+// New crea un cliente para la API en baseURL (DefaultBaseURL si viene vacío)
+// con un timeout de DefaultTimeout por solicitud.
+func New(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// APIError es un error devuelto por la API con el formato de error común
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error %d (%s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Drivers devuelve todos los corredores (GET /api/corredor)
+func (c *Client) Drivers() ([]models.Driver, error) {
+	var drivers []models.Driver
+	if err := c.get("/api/corredor", nil, &drivers); err != nil {
+		return nil, err
+	}
+	return drivers, nil
+}
+
+// ListOptions son la paginación y el orden de un listado. Limit 0 significa sin límite;
+// Sort es un campo del listado, con prefijo "-" para orden descendente.
+type ListOptions struct {
+	Limit  int
+	Offset int
+	Sort   string
+}
+
+func (o ListOptions) values() url.Values {
+	query := url.Values{}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		query.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	return query
+}
+
+// DriverQuery filtra y pagina GET /api/corredor. Los filtros vacíos no se envían.
+type DriverQuery struct {
+	ListOptions
+	Team    string
+	Country string
+}
+
+// SearchDrivers devuelve una página de corredores y el total que cumple los filtros
+func (c *Client) SearchDrivers(q DriverQuery) ([]models.Driver, int, error) {
+	query := q.values()
+	if q.Team != "" {
+		query.Set("team", q.Team)
+	}
+	if q.Country != "" {
+		query.Set("country", q.Country)
+	}
+	var drivers []models.Driver
+	total, err := c.getList("/api/corredor", query, &drivers)
+	if err != nil {
+		return nil, 0, err
+	}
+	return drivers, total, nil
+}
+
+// DriverDetail devuelve el resumen y los resultados de un piloto (GET /api/corredor/detalle/:id)
+func (c *Client) DriverDetail(driverNumber int) (*models.DriverDetail, error) {
+	var detail models.DriverDetail
+	if err := c.get("/api/corredor/detalle/"+strconv.Itoa(driverNumber), nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// Races devuelve las carreras de una temporada, o todas si year es 0 (GET /api/carrera)
+func (c *Client) Races(year int) ([]models.Race, error) {
+	query := url.Values{}
+	if year != 0 {
+		query.Set("year", strconv.Itoa(year))
+	}
+	var races []models.Race
+	if err := c.get("/api/carrera", query, &races); err != nil {
+		return nil, err
+	}
+	return races, nil
+}
+
+// RaceQuery filtra y pagina GET /api/carrera. Year 0 y los filtros vacíos no se envían.
+type RaceQuery struct {
+	ListOptions
+	Year    int
+	Country string
+	Circuit string
+}
+
+// SearchRaces devuelve una página de carreras y el total que cumple los filtros
+func (c *Client) SearchRaces(q RaceQuery) ([]models.Race, int, error) {
+	query := q.values()
+	if q.Year != 0 {
+		query.Set("year", strconv.Itoa(q.Year))
+	}
+	if q.Country != "" {
+		query.Set("country", q.Country)
+	}
+	if q.Circuit != "" {
+		query.Set("circuit", q.Circuit)
+	}
+	var races []models.Race
+	total, err := c.getList("/api/carrera", query, &races)
+	if err != nil {
+		return nil, 0, err
+	}
+	return races, total, nil
+}
+
+// RaceDetail devuelve el detalle de una carrera (GET /api/carrera/detalle/:id)
+func (c *Client) RaceDetail(sessionKey int) (*models.RaceDetail, error) {
+	var detail models.RaceDetail
+	if err := c.get("/api/carrera/detalle/"+strconv.Itoa(sessionKey), nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// LapChart devuelve las posiciones vuelta a vuelta de una carrera (GET /api/carrera/detalle/:id/vueltas)
+func (c *Client) LapChart(sessionKey int) (*models.LapChart, error) {
+	var chart models.LapChart
+	if err := c.get("/api/carrera/detalle/"+strconv.Itoa(sessionKey)+"/vueltas", nil, &chart); err != nil {
+		return nil, err
+	}
+	return &chart, nil
+}
+
+// SeasonSummary devuelve los rankings de una temporada (GET /api/temporada/resumen)
+func (c *Client) SeasonSummary(year int) (*models.SeasonSummary, error) {
+	query := url.Values{}
+	query.Set("year", strconv.Itoa(year))
+	var summary models.SeasonSummary
+	if err := c.get("/api/temporada/resumen", query, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Circuits devuelve todos los circuitos (GET /api/circuito)
+func (c *Client) Circuits() ([]models.Circuit, error) {
+	var circuits []models.Circuit
+	if err := c.get("/api/circuito", nil, &circuits); err != nil {
+		return nil, err
+	}
+	return circuits, nil
+}
+
+// CircuitDetail devuelve el historial de un circuito (GET /api/circuito/:key)
+func (c *Client) CircuitDetail(circuitKey int) (*models.CircuitDetail, error) {
+	var detail models.CircuitDetail
+	if err := c.get("/api/circuito/"+strconv.Itoa(circuitKey), nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// get hace un GET a path con los parámetros de query indicados y decodifica la respuesta en out.
+// Las respuestas que no son 200 se devuelven como *APIError.
+func (c *Client) get(path string, query url.Values, out interface{}) error {
+	_, err := c.do(path, query, out)
+	return err
+}
+
+// getList es get para listados paginados: además devuelve el total de X-Total-Count
+func (c *Client) getList(path string, query url.Values, out interface{}) (int, error) {
+	header, err := c.do(path, query, out)
+	if err != nil {
+		return 0, err
+	}
+	total, err := strconv.Atoi(header.Get("X-Total-Count"))
+	if err != nil {
+		return 0, fmt.Errorf("la respuesta no incluye un X-Total-Count válido")
+	}
+	return total, nil
+}
+
+// do hace la solicitud de get y además devuelve los headers de la respuesta
+func (c *Client) do(path string, query url.Values, out interface{}) (http.Header, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error al armar la solicitud: %v", err)
+	}
+	if c.Lang != "" {
+		req.Header.Set("Accept-Language", c.Lang)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al conectar con el servidor: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la respuesta: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+		var envelope models.ErrorResponse
+		if err := json.Unmarshal(body, &envelope); err == nil && envelope.Error.Message != "" {
+			apiErr.Code = envelope.Error.Code
+			apiErr.Message = envelope.Error.Message
+		}
+		return nil, apiErr
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return nil, fmt.Errorf("error al parsear la respuesta: %v", err)
+	}
+	return resp.Header, nil
+}
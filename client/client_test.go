@@ -0,0 +1,214 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// request es lo que recibió el servidor de prueba
+type request struct {
+	path           string
+	query          string
+	acceptLanguage string
+}
+
+// newTestServer responde siempre con status, los headers y body indicados, y guarda la
+// última solicitud en last
+func newTestServer(t *testing.T, status int, header map[string]string, body string, last *request) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*last = request{path: r.URL.Path, query: r.URL.RawQuery, acceptLanguage: r.Header.Get("Accept-Language")}
+		for name, value := range header {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return New(server.URL + "/")
+}
+
+func TestNew(t *testing.T) {
+	if c := New(""); c.BaseURL != DefaultBaseURL || c.HTTPClient.Timeout != DefaultTimeout {
+		t.Errorf("New(\"\") = %+v, se esperaba %s con timeout %v", c, DefaultBaseURL, DefaultTimeout)
+	}
+	if c := New("http://localhost:9090/"); c.BaseURL != "http://localhost:9090" {
+		t.Errorf("BaseURL = %q, se esperaba sin la barra final", c.BaseURL)
+	}
+}
+
+func TestSearchDrivers(t *testing.T) {
+	var last request
+	c := newTestServer(t, 200,
+		map[string]string{"Content-Type": "application/json", "X-Total-Count": "20"},
+		`[{"first_name": "Max", "last_name": "Verstappen", "driver_number": 1, "team_name": "Red Bull Racing", "country_code": "NED"}]`,
+		&last)
+	c.Lang = "en"
+
+	drivers, total, err := c.SearchDrivers(DriverQuery{
+		ListOptions: ListOptions{Limit: 1, Offset: 5, Sort: "-driver_number"},
+		Team:        "Red Bull Racing",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 20 {
+		t.Errorf("total = %d, se esperaba 20 de X-Total-Count", total)
+	}
+	if len(drivers) != 1 || drivers[0].DriverNumber != 1 || drivers[0].CountryCode == nil || *drivers[0].CountryCode != "NED" {
+		t.Errorf("drivers = %+v", drivers)
+	}
+
+	want := request{
+		path:           "/api/corredor",
+		query:          "limit=1&offset=5&sort=-driver_number&team=Red+Bull+Racing",
+		acceptLanguage: "en",
+	}
+	if last != want {
+		t.Errorf("solicitud %+v, se esperaba %+v", last, want)
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	for _, lang := range []string{"", "es", "en"} {
+		var last request
+		c := newTestServer(t, 200, nil, `[]`, &last)
+		c.Lang = lang
+
+		if _, err := c.Circuits(); err != nil {
+			t.Fatal(err)
+		}
+		if last.acceptLanguage != lang {
+			t.Errorf("con Lang %q se envió Accept-Language %q", lang, last.acceptLanguage)
+		}
+	}
+}
+
+func TestTotalCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+		// err es un fragmento del error esperado ("" si no hay error)
+		err string
+	}{
+		{name: "total", header: map[string]string{"X-Total-Count": "3"}, want: 3},
+		{name: "total en cero", header: map[string]string{"X-Total-Count": "0"}, want: 0},
+		{name: "sin X-Total-Count", err: "X-Total-Count"},
+		{name: "X-Total-Count inválido", header: map[string]string{"X-Total-Count": "muchos"}, err: "X-Total-Count"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var last request
+			c := newTestServer(t, 200, tc.header, `[]`, &last)
+
+			_, total, err := c.SearchRaces(RaceQuery{Year: 2024, Country: "Bahrain"})
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("SearchRaces = %v, se esperaba un error con %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != tc.want {
+				t.Errorf("total = %d, se esperaba %d", total, tc.want)
+			}
+			if last.path != "/api/carrera" || last.query != "country=Bahrain&year=2024" {
+				t.Errorf("solicitud %+v", last)
+			}
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		want   APIError
+	}{
+		{
+			name:   "formato de error común",
+			status: 404,
+			header: map[string]string{"Content-Type": "application/json"},
+			body:   `{"error": {"code": "driver_not_found", "message": "Corredor no encontrado"}}`,
+			want:   APIError{StatusCode: 404, Code: "driver_not_found", Message: "Corredor no encontrado"},
+		},
+		{
+			name:   "texto plano",
+			status: 502,
+			header: map[string]string{"Content-Type": "text/plain"},
+			body:   "Bad Gateway",
+			want:   APIError{StatusCode: 502, Message: "Bad Gateway"},
+		},
+		{
+			name:   "HTML de un proxy",
+			status: 503,
+			header: map[string]string{"Content-Type": "text/html"},
+			body:   "<html><body>Mantenimiento</body></html>",
+			want:   APIError{StatusCode: 503, Message: "<html><body>Mantenimiento</body></html>"},
+		},
+		{
+			name:   "JSON sin el formato de error común",
+			status: 500,
+			header: map[string]string{"Content-Type": "application/json"},
+			body:   `{"detail": "error interno"}`,
+			want:   APIError{StatusCode: 500, Message: `{"detail": "error interno"}`},
+		},
+		{
+			name:   "sin cuerpo",
+			status: 500,
+			want:   APIError{StatusCode: 500},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var last request
+			c := newTestServer(t, tc.status, tc.header, tc.body, &last)
+
+			_, err := c.DriverDetail(99)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("DriverDetail = %v, se esperaba un *APIError", err)
+			}
+			if !reflect.DeepEqual(*apiErr, tc.want) {
+				t.Errorf("APIError = %+v, se esperaba %+v", *apiErr, tc.want)
+			}
+			if last.path != "/api/corredor/detalle/99" {
+				t.Errorf("solicitud a %s", last.path)
+			}
+		})
+	}
+}
+
+func TestInvalidResponse(t *testing.T) {
+	var last request
+	c := newTestServer(t, 200, map[string]string{"Content-Type": "text/html"}, "<html></html>", &last)
+
+	_, err := c.SeasonSummary(2024)
+	var apiErr *APIError
+	if err == nil || errors.As(err, &apiErr) || !strings.Contains(err.Error(), "error al parsear la respuesta") {
+		t.Errorf("SeasonSummary = %v, se esperaba un error al parsear la respuesta", err)
+	}
+	if last.path != "/api/temporada/resumen" || last.query != "year=2024" {
+		t.Errorf("solicitud %+v", last)
+	}
+}
+
+func TestConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	c := New(server.URL)
+
+	_, err := c.Drivers()
+	if err == nil || !strings.Contains(err.Error(), "error al conectar con el servidor") {
+		t.Errorf("Drivers = %v, se esperaba un error de conexión", err)
+	}
+}
@@ -2,165 +2,164 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"f1_statshub_system/client"
+	"f1_statshub_system/config"
+	"f1_statshub_system/i18n"
 )
 
+// mostrarError imprime un error de la API con su mensaje, o el error de conexión tal cual
+func mostrarError(lang i18n.Lang, err error) {
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		fmt.Println(lang.T("error.server", apiErr.Message))
+		return
+	}
+	fmt.Println(lang.T("error.generic", err))
+}
+
+// leerNumero lee una línea de la entrada y la interpreta como entero
+func leerNumero(reader *bufio.Reader) (int, error) {
+	input, _ := reader.ReadString('\n')
+	return strconv.Atoi(strings.TrimSpace(input))
+}
+
+// formatoSector muestra un tiempo de sector con 3 decimales, o "-" si la API no lo informó
+func formatoSector(sector *float64) string {
+	if sector == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", *sector)
+}
+
+// formatoOpcional muestra valor con formato, o "-" si la API no lo informó
+func formatoOpcional(formato string, valor *float64) string {
+	if valor == nil {
+		return "-"
+	}
+	return fmt.Sprintf(formato, *valor)
+}
+
+// formatoPais muestra el código de país de un piloto, o "-" si la API no lo informó
+func formatoPais(pais *string) string {
+	if pais == nil {
+		return "-"
+	}
+	return *pais
+}
+
 func main() {
+	// Configuración: valores por defecto, f1stats.json, variables de entorno y flags (ver config)
+	cfg, err := config.LoadClient(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	lang, ok := i18n.Parse(cfg.Lang)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Idioma no soportado:", cfg.Lang)
+		os.Exit(2)
+	}
+
 	reader := bufio.NewReader(os.Stdin)
+	api := client.New(cfg.APIURL)
+	api.Lang = string(lang)
 
 	for {
-		fmt.Println("===== MENÚ PRINCIPAL =====")
-		fmt.Println("1. Ver todos los corredores")
-		fmt.Println("2. Ver detalle de un corredor")
-		fmt.Println("3. Ver todas las carreras")
-		fmt.Println("4. Ver detalle de una carrera")
-		fmt.Println("5. Ver resumen de temporada")
-		fmt.Println("6. Salir")
-		fmt.Print("Selecciona una opción: ")
+		fmt.Println(lang.T("menu.title"))
+		fmt.Println(lang.T("menu.drivers"))
+		fmt.Println(lang.T("menu.driver_detail"))
+		fmt.Println(lang.T("menu.races"))
+		fmt.Println(lang.T("menu.race_detail"))
+		fmt.Println(lang.T("menu.season"))
+		fmt.Println(lang.T("menu.exit"))
+		fmt.Print(lang.T("menu.prompt"))
 
 		input, _ := reader.ReadString('\n')
 		input = strings.TrimSpace(input)
 
 		option, err := strconv.Atoi(input)
 		if err != nil {
-			fmt.Println("❌ Opción inválida. Intenta nuevamente.\n")
+			fmt.Println(lang.T("menu.invalid"))
 			continue
 		}
 
 		switch option {
 		case 1:
-			fmt.Println("\n🔎 Obteniendo lista de corredores...")
+			fmt.Println(lang.T("drivers.loading"))
 		
-			resp, err := http.Get("http://localhost:8080/api/corredor")
+			corredores, err := api.Drivers()
 			if err != nil {
-				fmt.Println(" Error al conectar con el servidor:", err)
-				break
-			}
-			defer resp.Body.Close()
-		
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				fmt.Println(" Error al leer la respuesta:", err)
-				break
-			}
-		
-			var corredores []struct {
-				FirstName    string `json:"first_name"`
-				LastName     string `json:"last_name"`
-				DriverNumber int    `json:"driver_number"`
-				TeamName     string `json:"team_name"`
-				CountryCode  string `json:"country_code"`
-			}
-		
-			if err := json.Unmarshal(body, &corredores); err != nil {
-				fmt.Println("❌ Error al parsear los datos:", err)
+				mostrarError(lang, err)
 				break
 			}
 		
 			// Imprimir tabla
-			fmt.Println("\n| # | Nombre   | Apellido     | N Piloto | Equipo            | País |")
+			fmt.Println(lang.T("drivers.header"))
 			fmt.Println("---------------------------------------------------------------------")
 			for i, c := range corredores {
 				fmt.Printf("| %-2d| %-8s | %-12s | %-8d | %-17s | %-4s |\n",
-					i+1, c.FirstName, c.LastName, c.DriverNumber, c.TeamName, c.CountryCode)
+					i+1, c.FirstName, c.LastName, c.DriverNumber, c.TeamName, formatoPais(c.CountryCode))
 			}
 			fmt.Println()
 		case 2:
-			fmt.Print("\nIngrese el numero del piloto: ")
-			idInput, _ := reader.ReadString('\n')
-			idInput = strings.TrimSpace(idInput)
-		
-			url := fmt.Sprintf("http://localhost:8080/api/corredor/detalle/%s", idInput)
-			resp, err := http.Get(url)
+			fmt.Print(lang.T("driver.prompt"))
+			driverNumber, err := leerNumero(reader)
 			if err != nil {
-				fmt.Println(" Error al conectar con el servidor:", err)
+				fmt.Println(lang.T("driver.invalid"))
 				break
 			}
-			defer resp.Body.Close()
-		
-			body, err := io.ReadAll(resp.Body)
+
+			detalle, err := api.DriverDetail(driverNumber)
 			if err != nil {
-				fmt.Println(" Error al leer la respuesta:", err)
-				break
-			}
-		
-			var detalle struct {
-				DriverID           string `json:"driver_id"`
-				PerformanceSummary struct {
-					Wins     int     `json:"wins"`
-					Top3Fin  int     `json:"top_3_finishes"`
-					MaxSpeed float64 `json:"max_speed"`
-				} `json:"performance_summary"`
-				RaceResults []struct {
-					SessionKey       int     `json:"session_key"`
-					Race             string  `json:"race"`
-					CircuitShortName string  `json:"circuit_short_name"`
-					Position         int     `json:"position"`
-					FastestLap       bool    `json:"fastest_lap"`
-					MaxSpeed         float64 `json:"max_speed"`
-					BestLapDuration  float64 `json:"best_lap_duration"`
-				} `json:"race_results"`
-			}
-		
-			if err := json.Unmarshal(body, &detalle); err != nil {
-				fmt.Println(" Error al parsear los datos:", err)
+				mostrarError(lang, err)
 				break
 			}
 		
 			fmt.Println("\n====================================================================================================")
-			fmt.Println("| # | Carrera                | Pos Final | Vuelta rápida | Velocidad max | Menor tiempo vuelta     |")
+			fmt.Println(lang.T("driver.results_header"))
 			fmt.Println("====================================================================================================")
 		
 			for i, r := range detalle.RaceResults {
-				vueltaRapida := "No"
+				vueltaRapida := lang.T("no")
 				if r.FastestLap {
-					vueltaRapida = "Sí"
+					vueltaRapida = lang.T("yes")
 				}
-				fmt.Printf("| %-2d| %-23s | %-9d | %-13s | %-14.0f | %-21.3f |\n",
-					i+1, r.Race, r.Position, vueltaRapida, r.MaxSpeed, r.BestLapDuration)
+				fmt.Printf("| %-2d| %-23s | %-9d | %-13s | %-14s | %-21s |\n",
+					i+1, r.Race, r.Position, vueltaRapida, formatoOpcional("%.0f", r.MaxSpeed), formatoOpcional("%.3f", r.BestLapDuration))
 			}
 			fmt.Println("====================================================================================================")
 		
 			fmt.Println("\n============================")
-			fmt.Println("| Resumen del piloto       |")
+			fmt.Println(lang.T("driver.summary_title"))
 			fmt.Println("============================")
-			fmt.Printf("| Carreras ganadas         | %-4d |\n", detalle.PerformanceSummary.Wins)
-			fmt.Printf("| Veces en el top 3        | %-4d |\n", detalle.PerformanceSummary.Top3Fin)
-			fmt.Printf("| Velocidad máxima alcanzada | %.0f km/h |\n", detalle.PerformanceSummary.MaxSpeed)
+			fmt.Println(lang.T("driver.wins", detalle.PerformanceSummary.Wins))
+			fmt.Println(lang.T("driver.top3", detalle.PerformanceSummary.Top3Finishes))
+			fmt.Println(lang.T("driver.max_speed", formatoOpcional("%.0f", detalle.PerformanceSummary.MaxSpeed)))
+			fmt.Println(lang.T("driver.laps_led", detalle.PerformanceSummary.LapsLed))
 			fmt.Println("============================")
-			fmt.Println("\n===== MENÚ PRINCIPAL =====")
+			fmt.Println("\n" + lang.T("menu.title"))
 		case 3:
-			fmt.Println("[3] Ver todas las carreras\n")
+			fmt.Println(lang.T("races.title"))
 		
-			resp, err := http.Get("http://localhost:8080/api/carrera")
+			carreras, err := api.Races(0)
 			if err != nil {
-				fmt.Println(" Error al hacer la solicitud:", err)
-				break
-			}
-			defer resp.Body.Close()
-		
-			var carreras []struct {
-				SessionKey        int    `json:"session_key"`
-				CountryName       string `json:"country_name"`
-				DateStart         string `json:"date_start"`
-				Year              int    `json:"year"`
-				CircuitShortName  string `json:"circuit_short_name"`
-			}
-		
-			if err := json.NewDecoder(resp.Body).Decode(&carreras); err != nil {
-				fmt.Println("❌ Error al leer la respuesta:", err)
+				mostrarError(lang, err)
 				break
 			}
 		
 			// Encabezado de tabla
-			fmt.Printf("| %-3s | %-10s | %-15s | %-12s | %-6s | %-20s |\n", "#", "ID carrera", "País", "Fecha", "Year", "Circuito")
+			fmt.Printf("| %-3s | %-10s | %-15s | %-12s | %-6s | %-20s |\n", "#", lang.T("races.col.id"), lang.T("races.col.country"), lang.T("races.col.date"), lang.T("races.col.year"), lang.T("races.col.circuit"))
 			fmt.Println(strings.Repeat("-", 80))
 		
 			for i, c := range carreras {
@@ -171,52 +170,17 @@ func main() {
 			}
 			fmt.Println(strings.Repeat("-", 80))
 		case 4:
-			fmt.Println("▶️ [4] Ver detalle de carrera\n")
-			fmt.Print("Ingrese el número de la carrera: ")
-			var raceID string
-			fmt.Scanln(&raceID)
-			url := fmt.Sprintf("http://localhost:8080/api/carrera/detalle/%s", raceID)
-			resp, err := http.Get(url)
+			fmt.Println(lang.T("race.title"))
+			fmt.Print(lang.T("race.prompt"))
+			raceID, err := leerNumero(reader)
 			if err != nil {
-				fmt.Println(" Error al hacer la solicitud:", err)
-				break
-			}
-			defer resp.Body.Close()
-		
-			body, _ := io.ReadAll(resp.Body)
-		
-			if resp.StatusCode != 200 {
-				fmt.Println(" Error en la respuesta del servidor:", string(body))
+				fmt.Println(lang.T("race.invalid"))
 				break
 			}
-		
-			var detalle struct {
-				RaceID           string `json:"race_id"`
-				CountryName      string `json:"country_name"`
-				DateStart        string `json:"date_start"`
-				Year             int    `json:"year"`
-				CircuitShortName string `json:"circuit_short_name"`
-				Results []struct {
-					Position interface{} `json:"position"` // <- aquí el cambio
-					Driver   string      `json:"driver"`
-					Team     string      `json:"team"`
-					Country  string      `json:"country"`
-				} `json:"results"`
-				FastestLap struct {
-					Driver     string  `json:"driver"`
-					TotalTime  float64 `json:"total_time"`
-					Sector1    float64 `json:"sector_1"`
-					Sector2    float64 `json:"sector_2"`
-					Sector3    float64 `json:"sector_3"`
-				} `json:"fastest_lap"`
-				MaxSpeed struct {
-					Driver   string  `json:"driver"`
-					SpeedKMH float64 `json:"speed_kmh"`
-				} `json:"max_speed"`
-			}
-		
-			if err := json.Unmarshal(body, &detalle); err != nil {
-				fmt.Println("❌ Error al decodificar JSON:", err)
+
+			detalle, err := api.RaceDetail(raceID)
+			if err != nil {
+				mostrarError(lang, err)
 				break
 			}
 		
@@ -224,125 +188,122 @@ func main() {
 			fechaFormateada := t.Format("02-01-2006")
 		
 			// Header
-			fmt.Printf("\n Detalle de carrera: %s (%s)\nFecha: %s | Circuito: %s | Año: %d\n\n",
-				detalle.RaceID, detalle.CountryName, fechaFormateada, detalle.CircuitShortName, detalle.Year)
+			fmt.Println(lang.T("race.header",
+				detalle.RaceID, detalle.CountryName, fechaFormateada, detalle.CircuitShortName, detalle.Year))
 		
-			fmt.Println("| Resultados                                                    |")
+			fmt.Println(lang.T("race.results_title"))
 			fmt.Println("|--------------------------------------------------------------|")
-			fmt.Println("| Posicion | Piloto              | Equipo          | Pais      |")
+			fmt.Println(lang.T("race.results_header"))
 			fmt.Println("|--------------------------------------------------------------|")
 			for _, r := range detalle.Results {
-				fmt.Printf("| %-8s | %-18s | %-14s | %-9s |\n",fmt.Sprintf("%v", r.Position), r.Driver, r.Team, r.Country)
+				fmt.Printf("| %-8s | %-18s | %-14s | %-9s |\n",r.Position.String(), r.Driver, r.Team, formatoPais(r.Country))
 			}
 			fmt.Println("|--------------------------------------------------------------|")
 		
 			// Vuelta más rápida
-			fmt.Println("\n| Vuelta más rápida                                            |")
+			fmt.Println(lang.T("race.fastest_title"))
 			fmt.Println("|--------------------------------------------------------------|")
-			fmt.Println("| Piloto          | Tiempo Total | Sector 1 | Sector 2 | Sector 3 |")
+			fmt.Println(lang.T("race.fastest_header"))
 			fmt.Println("|--------------------------------------------------------------|")
-			fmt.Printf("| %-15s | %11s | %8.3f | %8.3f | %8.3f |\n",
+			fmt.Printf("| %-15s | %11s | %8s | %8s | %8s |\n",
 				detalle.FastestLap.Driver,
 				fmt.Sprintf("%.3f", detalle.FastestLap.TotalTime),
-				detalle.FastestLap.Sector1,
-				detalle.FastestLap.Sector2,
-				detalle.FastestLap.Sector3,
+				formatoSector(detalle.FastestLap.Sector1),
+				formatoSector(detalle.FastestLap.Sector2),
+				formatoSector(detalle.FastestLap.Sector3),
 			)
 			fmt.Println("|--------------------------------------------------------------|")
+			if len(detalle.FastestLap.TiedDrivers) > 0 {
+				fmt.Println(lang.T("race.tied", strings.Join(detalle.FastestLap.TiedDrivers, ", ")))
+			}
 		
 			// Velocidad máxima
-			fmt.Println("\n| Velocidad máxima alcanzada                                   |")
+			fmt.Println(lang.T("race.max_speed_title"))
 			fmt.Println("|--------------------------------------------------------------|")
-			fmt.Println("| Piloto          | Velocidad (km/h)                           |")
+			fmt.Println(lang.T("race.max_speed_header"))
 			fmt.Println("|--------------------------------------------------------------|")
 			fmt.Printf("| %-15s | %-34.1f |\n", detalle.MaxSpeed.Driver, detalle.MaxSpeed.SpeedKMH)
 			fmt.Println("|--------------------------------------------------------------|")
+
+			// Vueltas lideradas
+			fmt.Println(lang.T("race.laps_led_title"))
+			fmt.Println("|--------------------------------------------------------------|")
+			fmt.Println(lang.T("race.laps_led_header"))
+			fmt.Println("|--------------------------------------------------------------|")
+			for _, l := range detalle.LapsLed {
+				fmt.Printf("| %-15s | %-42d |\n", l.Driver, l.Laps)
+			}
+			fmt.Println("|--------------------------------------------------------------|")
+			fmt.Println(lang.T("race.lead_changes", detalle.LeadChanges))
+
+			// Récord del circuito
+			if detalle.CircuitRecord != nil {
+				fmt.Println(lang.T("race.circuit_record",
+					detalle.CircuitRecord.LapDuration, detalle.CircuitRecord.Driver, detalle.CircuitRecord.Year))
+			}
+			if detalle.NewCircuitRecord {
+				fmt.Println(lang.T("race.new_record"))
+			}
 		
 		case 5:
-			fmt.Println(" [5] Ver resumen de temporada\n")
-			fmt.Print("Ingrese la temporada (ej: 2024): ")
-			var temporada string
-			fmt.Scanln(&temporada)
-		
-			url := "http://localhost:8080/api/temporada/resumen"
-			resp, err := http.Get(url)
+			fmt.Println(lang.T("season.title"))
+			fmt.Print(lang.T("season.prompt"))
+			temporada, err := leerNumero(reader)
 			if err != nil {
-				fmt.Println("❌ Error al hacer la solicitud:", err)
+				fmt.Println(lang.T("season.invalid"))
 				break
 			}
-			defer resp.Body.Close()
-		
-			body, _ := io.ReadAll(resp.Body)
-		
-			if resp.StatusCode != 200 {
-				fmt.Println(" Error en la respuesta del servidor:", string(body))
-				break
-			}
-		
-			var resumen struct {
-				Season          int `json:"season"`
-				Top3Winners     []struct {
-					Position int    `json:"position"`
-					Driver   string `json:"driver"`
-					Team     string `json:"team"`
-					Country  string `json:"country"`
-					Wins     int    `json:"wins"`
-				} `json:"top_3_winners"`
-				Top3FastestLaps []struct {
-					Position    int    `json:"position"`
-					Driver      string `json:"driver"`
-					Team        string `json:"team"`
-					Country     string `json:"country"`
-					FastestLaps int    `json:"fastest_laps"`
-				} `json:"top_3_fastest_laps"`
-				Top3PolePositions []struct {
-					Position int    `json:"position"`
-					Driver   string `json:"driver"`
-					Team     string `json:"team"`
-					Country  string `json:"country"`
-					Poles    int    `json:"poles"`
-				} `json:"top_3_pole_positions"`
-			}
-		
-			if err := json.Unmarshal(body, &resumen); err != nil {
-				fmt.Println("❌ Error al decodificar JSON:", err)
+
+			resumen, err := api.SeasonSummary(temporada)
+			if err != nil {
+				mostrarError(lang, err)
 				break
 			}
 		
-			fmt.Printf("\n Top 3 Pilotos con más Victorias - Temporada %d\n", resumen.Season)
+			fmt.Println(lang.T("season.winners_title", resumen.Season))
 			fmt.Println("------------------------------------------------------------")
-			fmt.Println("| Posición | Piloto           | Equipo         | País | Victorias |")
+			fmt.Println(lang.T("season.winners_header"))
 			fmt.Println("------------------------------------------------------------")
 			for _, p := range resumen.Top3Winners {
 				fmt.Printf("| %-8d | %-15s | %-14s | %-4s | %-9d |\n",
-					p.Position, p.Driver, p.Team, p.Country, p.Wins)
+					p.Position, p.Driver, p.Team, formatoPais(p.Country), p.Wins)
 			}
-			fmt.Println("------------------------------------------------------------\n")
+			fmt.Print("------------------------------------------------------------\n\n")
 		
-			fmt.Printf(" Top 3 Pilotos con más Vueltas Rápidas - Temporada %d\n", resumen.Season)
+			fmt.Println(lang.T("season.fastest_title", resumen.Season))
 			fmt.Println("------------------------------------------------------------")
-			fmt.Println("| Posición | Piloto           | Equipo         | País | Vueltas Rápidas |")
+			fmt.Println(lang.T("season.fastest_header"))
 			fmt.Println("------------------------------------------------------------")
 			for _, p := range resumen.Top3FastestLaps {
 				fmt.Printf("| %-8d | %-15s | %-14s | %-4s | %-16d |\n",
-					p.Position, p.Driver, p.Team, p.Country, p.FastestLaps)
+					p.Position, p.Driver, p.Team, formatoPais(p.Country), p.FastestLaps)
 			}
-			fmt.Println("------------------------------------------------------------\n")
+			fmt.Print("------------------------------------------------------------\n\n")
 		
-			fmt.Printf(" Top 3 Pilotos con más Pole Positions - Temporada %d\n", resumen.Season)
+			fmt.Println(lang.T("season.podiums_title", resumen.Season))
 			fmt.Println("------------------------------------------------------------")
-			fmt.Println("| Posición | Piloto           | Equipo         | País | Poles |")
+			fmt.Println(lang.T("season.podiums_header"))
 			fmt.Println("------------------------------------------------------------")
-			for _, p := range resumen.Top3PolePositions {
-				fmt.Printf("| %-8d | %-15s | %-14s | %-4s | %-5d |\n",
-					p.Position, p.Driver, p.Team, p.Country, p.Poles)
+			for _, p := range resumen.Top3Podiums {
+				fmt.Printf("| %-8d | %-15s | %-14s | %-4s | %-6d |\n",
+					p.Position, p.Driver, p.Team, formatoPais(p.Country), p.Podiums)
 			}
-			fmt.Println("------------------------------------------------------------\n")
+			fmt.Print("------------------------------------------------------------\n\n")
+
+			fmt.Println(lang.T("season.laps_led_title", resumen.Season))
+			fmt.Println("------------------------------------------------------------")
+			fmt.Println(lang.T("season.laps_led_header"))
+			fmt.Println("------------------------------------------------------------")
+			for _, p := range resumen.Top3LapsLed {
+				fmt.Printf("| %-8d | %-15s | %-14s | %-4s | %-17d |\n",
+					p.Position, p.Driver, p.Team, formatoPais(p.Country), p.LapsLed)
+			}
+			fmt.Println("------------------------------------------------------------")
 		case 6:
-			fmt.Println("👋 Saliendo del programa...")
+			fmt.Println(lang.T("menu.bye"))
 			return
 		default:
-			fmt.Println("❌ Opción no reconocida. Intenta nuevamente.\n")
+			fmt.Println(lang.T("menu.unknown"))
 		}
 	}
 }
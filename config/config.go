@@ -0,0 +1,309 @@
+// Package config reúne la configuración de server.go y cliente.go. Cada valor sale,
+// de menor a mayor prioridad, de los valores por defecto, del archivo de configuración
+// (JSON), de una variable de entorno y de un flag de la línea de comandos:
+//
+//	go run server.go -addr :9090 -seasons 2023,2024
+//	F1_API_URL=http://otro-host:8080 go run cliente.go
+//
+// El archivo es opcional: se usa el indicado por -config o F1_CONFIG, o f1stats.json en
+// el directorio actual si existe. Ver f1stats.example.json para el formato.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"f1_statshub_system/client"
+	"f1_statshub_system/i18n"
+	"f1_statshub_system/openf1"
+)
+
+// DefaultFile es el archivo de configuración que se lee si existe y no se indica otro
+const DefaultFile = "f1stats.json"
+
+// Config son los valores configurables de ambos binarios. Cada uno usa los suyos.
+type Config struct {
+	// Servidor
+	DBDriver   string `json:"db_driver"`
+	DBDSN      string `json:"db_dsn"`
+	ListenAddr string `json:"listen_addr"`
+	OpenF1URL  string `json:"openf1_url"`
+	// Tiempo máximo de cada solicitud a OpenF1, por ejemplo "30s"
+	OpenF1Timeout Duration `json:"openf1_timeout"`
+	Seasons       []int    `json:"seasons"`
+	BatchSize     int      `json:"batch_size"`
+	MaxRetries    int      `json:"max_retries"`
+	Workers       int      `json:"workers"`
+	// Solicitudes por segundo a OpenF1; 0 es sin límite
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	// Tiempo que el servidor guarda cada respuesta de la API en el cache, por ejemplo "5m"
+	CacheTTL Duration `json:"cache_ttl"`
+
+	// Cliente
+	APIURL string `json:"api_url"`
+	Lang   string `json:"lang"`
+}
+
+// Default devuelve la configuración que se usa si no se indica nada. DBDSN vacío
+// significa ./proxy.db con SQLite (ver storage.Open).
+func Default() Config {
+	return Config{
+		DBDriver:      "sqlite",
+		ListenAddr:    ":8080",
+		OpenF1URL:     "https://api.openf1.org",
+		OpenF1Timeout: Duration(openf1.DefaultTimeout),
+		Seasons:       []int{2024},
+		BatchSize:     100,
+		MaxRetries:    5,
+		Workers:       4,
+		CacheTTL:      Duration(5 * time.Minute),
+		APIURL:        client.DefaultBaseURL,
+		Lang:          string(i18n.Default),
+
+		// OpenF1 admite 3 solicitudes por segundo sin autenticación
+		RequestsPerSecond: 3,
+	}
+}
+
+// Duration es un time.Duration que en el archivo de configuración se escribe como
+// texto, por ejemplo "30s" o "1m30s"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("la duración debe ser un texto como \"30s\": %v", err)
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+	*d = Duration(value)
+	return nil
+}
+
+// setting es un valor que se puede configurar por variable de entorno y por flag
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	get   func(c *Config) string
+	set   func(c *Config, value string) error
+}
+
+var serverSettings = []setting{
+	{"db-driver", "F1_DB_DRIVER", "motor de base de datos (sqlite o postgres)",
+		func(c *Config) string { return c.DBDriver },
+		func(c *Config, v string) error { c.DBDriver = v; return nil }},
+	{"db-dsn", "F1_DB_DSN", "archivo de SQLite o cadena de conexión de PostgreSQL",
+		func(c *Config) string { return c.DBDSN },
+		func(c *Config, v string) error { c.DBDSN = v; return nil }},
+	{"addr", "F1_LISTEN_ADDR", "dirección en la que escucha la API",
+		func(c *Config) string { return c.ListenAddr },
+		func(c *Config, v string) error { c.ListenAddr = v; return nil }},
+	{"openf1-url", "F1_OPENF1_URL", "URL base de la API de OpenF1",
+		func(c *Config) string { return c.OpenF1URL },
+		func(c *Config, v string) error { c.OpenF1URL = strings.TrimRight(v, "/"); return nil }},
+	{"openf1-timeout", "F1_OPENF1_TIMEOUT", "tiempo máximo de cada solicitud a OpenF1 (por ejemplo 30s)",
+		func(c *Config) string { return time.Duration(c.OpenF1Timeout).String() },
+		func(c *Config, v string) error {
+			timeout, err := time.ParseDuration(v)
+			c.OpenF1Timeout = Duration(timeout)
+			return err
+		}},
+	{"seasons", "F1_SEASONS", "temporadas a ingerir, separadas por comas",
+		func(c *Config) string { return joinInts(c.Seasons) },
+		func(c *Config, v string) error {
+			seasons, err := splitInts(v)
+			c.Seasons = seasons
+			return err
+		}},
+	{"batch-size", "F1_BATCH_SIZE", "registros por transacción al ingerir",
+		func(c *Config) string { return strconv.Itoa(c.BatchSize) },
+		func(c *Config, v string) (err error) { c.BatchSize, err = strconv.Atoi(v); return err }},
+	{"retries", "F1_MAX_RETRIES", "intentos por lote si la base está bloqueada y por solicitud si OpenF1 falla (red, 429 o 5xx)",
+		func(c *Config) string { return strconv.Itoa(c.MaxRetries) },
+		func(c *Config, v string) (err error) { c.MaxRetries, err = strconv.Atoi(v); return err }},
+	{"workers", "F1_WORKERS", "sesiones que se descargan en paralelo al ingerir",
+		func(c *Config) string { return strconv.Itoa(c.Workers) },
+		func(c *Config, v string) (err error) { c.Workers, err = strconv.Atoi(v); return err }},
+	{"rate-limit", "F1_RATE_LIMIT", "solicitudes por segundo a OpenF1 (0 es sin límite)",
+		func(c *Config) string { return strconv.FormatFloat(c.RequestsPerSecond, 'g', -1, 64) },
+		func(c *Config, v string) (err error) {
+			c.RequestsPerSecond, err = strconv.ParseFloat(v, 64)
+			return err
+		}},
+	{"cache-ttl", "F1_CACHE_TTL", "tiempo que se guarda cada respuesta de la API en el cache (por ejemplo 5m)",
+		func(c *Config) string { return time.Duration(c.CacheTTL).String() },
+		func(c *Config, v string) error {
+			ttl, err := time.ParseDuration(v)
+			c.CacheTTL = Duration(ttl)
+			return err
+		}},
+}
+
+var clientSettings = []setting{
+	{"api-url", "F1_API_URL", "URL base de la API de estadísticas",
+		func(c *Config) string { return c.APIURL },
+		func(c *Config, v string) error { c.APIURL = strings.TrimRight(v, "/"); return nil }},
+	{"lang", "F1_LANG", "idioma de la interfaz (es o en)",
+		func(c *Config) string { return c.Lang },
+		func(c *Config, v string) error { c.Lang = v; return nil }},
+}
+
+// LoadServer arma la configuración de server.go a partir de args (sin el nombre del
+// programa) y devuelve además los argumentos que no son flags (los comandos)
+func LoadServer(args []string) (Config, []string, error) {
+	return load("server", serverSettings, args)
+}
+
+// LoadClient arma la configuración de cliente.go a partir de args (sin el nombre del programa)
+func LoadClient(args []string) (Config, error) {
+	cfg, _, err := load("cliente", clientSettings, args)
+	return cfg, err
+}
+
+func load(name string, settings []setting, args []string) (Config, []string, error) {
+	cfg := Default()
+
+	// 1. Flags: se leen primero para conocer -config, pero se aplican al final
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := fs.String("config", "", "archivo de configuración JSON (por defecto "+DefaultFile+" si existe; también F1_CONFIG)")
+	values := make(map[string]*string, len(settings))
+	byFlag := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		values[s.flag] = fs.String(s.flag, s.get(&cfg), s.usage+" (también "+s.env+")")
+		byFlag[s.flag] = s
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, nil, err
+	}
+
+	// 2. Archivo de configuración
+	path, required := *configFile, true
+	if path == "" {
+		path = os.Getenv("F1_CONFIG")
+	}
+	if path == "" {
+		path, required = DefaultFile, false
+	}
+	if err := readFile(path, required, &cfg); err != nil {
+		return cfg, nil, err
+	}
+
+	// 3. Variables de entorno
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(s.env); ok && value != "" {
+			if err := s.set(&cfg, value); err != nil {
+				return cfg, nil, fmt.Errorf("valor inválido en %s: %q", s.env, value)
+			}
+		}
+	}
+
+	// 4. Flags indicados explícitamente
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		s, ok := byFlag[f.Name]
+		if !ok || err != nil {
+			return
+		}
+		if s.set(&cfg, *values[s.flag]) != nil {
+			err = fmt.Errorf("valor inválido en -%s: %q", s.flag, *values[s.flag])
+		}
+	})
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	return cfg, fs.Args(), cfg.validate()
+}
+
+// readFile completa cfg con el archivo path. Si el archivo no existe y no fue pedido
+// explícitamente se ignora.
+func readFile(path string, required bool, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error al leer la configuración: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("error en el archivo de configuración %s: %v", path, err)
+	}
+	cfg.OpenF1URL = strings.TrimRight(cfg.OpenF1URL, "/")
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+	return nil
+}
+
+func (c Config) validate() error {
+	switch {
+	case c.ListenAddr == "":
+		return fmt.Errorf("la dirección de escucha no puede estar vacía")
+	case !validURL(c.OpenF1URL):
+		return fmt.Errorf("la URL de OpenF1 debe ser http o https: %q", c.OpenF1URL)
+	case !validURL(c.APIURL):
+		return fmt.Errorf("la URL de la API debe ser http o https: %q", c.APIURL)
+	case c.OpenF1Timeout <= 0:
+		return fmt.Errorf("el timeout de OpenF1 debe ser mayor que 0")
+	case len(c.Seasons) == 0:
+		return fmt.Errorf("hay que indicar al menos una temporada")
+	case c.BatchSize <= 0:
+		return fmt.Errorf("el tamaño de lote debe ser mayor que 0")
+	case c.MaxRetries <= 0:
+		return fmt.Errorf("la cantidad de intentos debe ser mayor que 0")
+	case c.Workers <= 0:
+		return fmt.Errorf("la cantidad de workers debe ser mayor que 0")
+	case c.RequestsPerSecond < 0 || math.IsNaN(c.RequestsPerSecond) || math.IsInf(c.RequestsPerSecond, 0):
+		return fmt.Errorf("el límite de solicitudes por segundo debe ser un número mayor o igual a 0")
+	case c.CacheTTL <= 0:
+		return fmt.Errorf("el tiempo de vida del cache debe ser mayor que 0")
+	}
+	for _, season := range c.Seasons {
+		if season <= 0 {
+			return fmt.Errorf("temporada inválida: %d", season)
+		}
+	}
+	return nil
+}
+
+func validURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func splitInts(raw string) ([]int, error) {
+	var values []int
+	for _, part := range strings.Split(raw, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,324 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv vacía las variables de entorno de la configuración durante el test, para que
+// no influyan las del entorno en que corren los tests
+func clearEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("F1_CONFIG", "")
+	for _, s := range append(append([]setting{}, serverSettings...), clientSettings...) {
+		t.Setenv(s.env, "")
+	}
+}
+
+// discardStderr descarta la salida de errores durante el test: el FlagSet escribe ahí la
+// ayuda con -h y con los flags desconocidos
+func discardStderr(t *testing.T) {
+	t.Helper()
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = devNull
+	t.Cleanup(func() {
+		os.Stderr = stderr
+		devNull.Close()
+	})
+}
+
+// writeFile escribe content en un archivo de configuración temporal y devuelve su ruta
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "f1stats.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadServerPrecedence(t *testing.T) {
+	file := `{"workers": 6, "cache_ttl": "10m", "seasons": [2023], "openf1_url": "http://archivo:8000/"}`
+
+	tests := []struct {
+		name string
+		// file es el contenido del archivo de configuración ("" sin archivo)
+		file string
+		env  map[string]string
+		args []string
+		// want modifica Default() con los valores esperados
+		want func(c *Config)
+	}{
+		{
+			name: "valores por defecto",
+			want: func(c *Config) {},
+		},
+		{
+			name: "archivo",
+			file: file,
+			want: func(c *Config) {
+				c.Workers = 6
+				c.CacheTTL = Duration(10 * time.Minute)
+				c.Seasons = []int{2023}
+				c.OpenF1URL = "http://archivo:8000"
+			},
+		},
+		{
+			name: "entorno sobre archivo",
+			file: file,
+			env:  map[string]string{"F1_WORKERS": "8", "F1_CACHE_TTL": "15m", "F1_SEASONS": "2022, 2023"},
+			want: func(c *Config) {
+				c.Workers = 8
+				c.CacheTTL = Duration(15 * time.Minute)
+				c.Seasons = []int{2022, 2023}
+				c.OpenF1URL = "http://archivo:8000"
+			},
+		},
+		{
+			name: "flags sobre entorno y archivo",
+			file: file,
+			env:  map[string]string{"F1_WORKERS": "8", "F1_CACHE_TTL": "15m", "F1_OPENF1_URL": "http://entorno:8000/"},
+			args: []string{"-workers", "10", "-cache-ttl", "20m", "-seasons", "2021,2024"},
+			want: func(c *Config) {
+				c.Workers = 10
+				c.CacheTTL = Duration(20 * time.Minute)
+				c.Seasons = []int{2021, 2024}
+				c.OpenF1URL = "http://entorno:8000"
+			},
+		},
+		{
+			// Un flag con el valor por defecto también gana sobre el entorno
+			name: "flag igual al valor por defecto",
+			env:  map[string]string{"F1_WORKERS": "8"},
+			args: []string{"-workers", "4"},
+			want: func(c *Config) {},
+		},
+		{
+			name: "variable de entorno vacía",
+			file: file,
+			env:  map[string]string{"F1_WORKERS": ""},
+			want: func(c *Config) {
+				c.Workers = 6
+				c.CacheTTL = Duration(10 * time.Minute)
+				c.Seasons = []int{2023}
+				c.OpenF1URL = "http://archivo:8000"
+			},
+		},
+		{
+			name: "todos los valores del servidor por flag",
+			args: []string{
+				"-db-driver", "postgres", "-db-dsn", "postgres://localhost/f1", "-addr", ":9090",
+				"-openf1-url", "http://localhost:9999", "-openf1-timeout", "5s", "-seasons", "2023",
+				"-batch-size", "50", "-retries", "2", "-workers", "1", "-rate-limit", "0.5", "-cache-ttl", "1m",
+			},
+			want: func(c *Config) {
+				c.DBDriver = "postgres"
+				c.DBDSN = "postgres://localhost/f1"
+				c.ListenAddr = ":9090"
+				c.OpenF1URL = "http://localhost:9999"
+				c.OpenF1Timeout = Duration(5 * time.Second)
+				c.Seasons = []int{2023}
+				c.BatchSize = 50
+				c.MaxRetries = 2
+				c.Workers = 1
+				c.RequestsPerSecond = 0.5
+				c.CacheTTL = Duration(time.Minute)
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clearEnv(t)
+			args := tc.args
+			if tc.file != "" {
+				args = append([]string{"-config", writeFile(t, tc.file)}, args...)
+			}
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, rest, err := LoadServer(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Default()
+			tc.want(&want)
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("LoadServer(%q) = %+v, se esperaba %+v", args, cfg, want)
+			}
+			if len(rest) != 0 {
+				t.Errorf("argumentos restantes %q, no se esperaba ninguno", rest)
+			}
+		})
+	}
+}
+
+func TestLoadServerConfigFromEnv(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("F1_CONFIG", writeFile(t, `{"batch_size": 25}`))
+
+	cfg, _, err := LoadServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BatchSize != 25 {
+		t.Errorf("BatchSize = %d, se esperaba 25 del archivo de F1_CONFIG", cfg.BatchSize)
+	}
+}
+
+func TestLoadServerCommands(t *testing.T) {
+	clearEnv(t)
+
+	cfg, rest, err := LoadServer([]string{"-workers", "2", "verify", "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Workers != 2 {
+		t.Errorf("Workers = %d, se esperaba 2", cfg.Workers)
+	}
+	if want := []string{"verify", "json"}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("argumentos restantes %q, se esperaba %q", rest, want)
+	}
+}
+
+func TestLoadServerHelp(t *testing.T) {
+	clearEnv(t)
+	discardStderr(t)
+
+	for _, arg := range []string{"-h", "-help"} {
+		if _, _, err := LoadServer([]string{arg}); !errors.Is(err, flag.ErrHelp) {
+			t.Errorf("LoadServer(%s) = %v, se esperaba flag.ErrHelp", arg, err)
+		}
+	}
+	if _, err := LoadClient([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("LoadClient(-h) = %v, se esperaba flag.ErrHelp", err)
+	}
+}
+
+func TestLoadServerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		// want es un fragmento del mensaje de error
+		want string
+	}{
+		// Archivo
+		{name: "duración inválida en el archivo", file: `{"cache_ttl": "pronto"}`, want: "f1stats.json"},
+		{name: "duración como número en el archivo", file: `{"openf1_timeout": 30}`, want: "la duración debe ser un texto"},
+		{name: "entero inválido en el archivo", file: `{"workers": "cuatro"}`, want: "f1stats.json"},
+		{name: "campo desconocido en el archivo", file: `{"wrokers": 4}`, want: "wrokers"},
+		{name: "JSON inválido en el archivo", file: `{"workers": 4,}`, want: "f1stats.json"},
+		{name: "archivo de -config inexistente", args: []string{"-config", "no-existe.json"}, want: "error al leer la configuración"},
+		{name: "archivo de F1_CONFIG inexistente", env: map[string]string{"F1_CONFIG": "no-existe.json"}, want: "error al leer la configuración"},
+
+		// Entorno
+		{name: "duración inválida en el entorno", env: map[string]string{"F1_CACHE_TTL": "pronto"}, want: "F1_CACHE_TTL"},
+		{name: "timeout inválido en el entorno", env: map[string]string{"F1_OPENF1_TIMEOUT": "30"}, want: "F1_OPENF1_TIMEOUT"},
+		{name: "entero inválido en el entorno", env: map[string]string{"F1_WORKERS": "cuatro"}, want: "F1_WORKERS"},
+		{name: "temporada inválida en el entorno", env: map[string]string{"F1_SEASONS": "2024,x"}, want: "F1_SEASONS"},
+		{name: "número inválido en el entorno", env: map[string]string{"F1_RATE_LIMIT": "rápido"}, want: "F1_RATE_LIMIT"},
+
+		// Flags
+		{name: "duración inválida en un flag", args: []string{"-cache-ttl", "pronto"}, want: "-cache-ttl"},
+		{name: "entero inválido en un flag", args: []string{"-batch-size", "cien"}, want: "-batch-size"},
+		{name: "entero inválido en otro flag", args: []string{"-retries", "1.5"}, want: "-retries"},
+		{name: "flag desconocido", args: []string{"-wrokers", "4"}, want: "wrokers"},
+
+		// Validación del resultado
+		{name: "workers en 0", args: []string{"-workers", "0"}, want: "workers"},
+		{name: "límite negativo", env: map[string]string{"F1_RATE_LIMIT": "-1"}, want: "límite de solicitudes"},
+		{name: "límite NaN", args: []string{"-rate-limit", "NaN"}, want: "límite de solicitudes"},
+		{name: "temporada negativa", file: `{"seasons": [-2024]}`, want: "temporada inválida"},
+		{name: "sin temporadas", file: `{"seasons": []}`, want: "al menos una temporada"},
+		{name: "URL que no es http", args: []string{"-openf1-url", "ftp://api.openf1.org"}, want: "URL de OpenF1"},
+		{name: "cache sin duración", args: []string{"-cache-ttl", "0s"}, want: "cache"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clearEnv(t)
+			args := tc.args
+			if tc.file != "" {
+				args = append([]string{"-config", writeFile(t, tc.file)}, args...)
+			}
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+			discardStderr(t)
+
+			_, _, err := LoadServer(args)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("LoadServer(%q) = %v, se esperaba un error con %q", args, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadClient(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		want func(c *Config)
+	}{
+		{name: "valores por defecto", want: func(c *Config) {}},
+		{
+			name: "archivo",
+			file: `{"api_url": "http://archivo:8080/", "lang": "en"}`,
+			want: func(c *Config) { c.APIURL = "http://archivo:8080"; c.Lang = "en" },
+		},
+		{
+			name: "entorno sobre archivo",
+			file: `{"api_url": "http://archivo:8080/", "lang": "en"}`,
+			env:  map[string]string{"F1_API_URL": "http://entorno:8080/"},
+			want: func(c *Config) { c.APIURL = "http://entorno:8080"; c.Lang = "en" },
+		},
+		{
+			name: "flags sobre entorno",
+			env:  map[string]string{"F1_API_URL": "http://entorno:8080", "F1_LANG": "en"},
+			args: []string{"-api-url", "http://flag:8080/", "-lang", "es"},
+			want: func(c *Config) { c.APIURL = "http://flag:8080"; c.Lang = "es" },
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clearEnv(t)
+			args := tc.args
+			if tc.file != "" {
+				args = append([]string{"-config", writeFile(t, tc.file)}, args...)
+			}
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := LoadClient(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Default()
+			tc.want(&want)
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("LoadClient(%q) = %+v, se esperaba %+v", args, cfg, want)
+			}
+		})
+	}
+
+	// Los flags del servidor no existen en el cliente
+	clearEnv(t)
+	discardStderr(t)
+	if _, err := LoadClient([]string{"-workers", "2"}); err == nil {
+		t.Error("LoadClient(-workers 2) no devolvió error")
+	}
+}
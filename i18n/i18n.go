@@ -0,0 +1,88 @@
+// Package i18n contiene los catálogos de mensajes en español e inglés. Los usan
+// api, para las etiquetas que genera en las respuestas (por ejemplo "GP de ..."),
+// y cliente.go, para todos los textos de la interfaz.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang es un idioma soportado, identificado por su código ISO 639-1
+type Lang string
+
+const (
+	Spanish Lang = "es"
+	English Lang = "en"
+)
+
+// Default es el idioma que se usa cuando no se pide ninguno soportado
+const Default = Spanish
+
+// Parse interpreta un código de idioma ("en", "EN", "en-US"...). Devuelve false si
+// el idioma no está soportado.
+func Parse(code string) (Lang, bool) {
+	base := strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	lang := Lang(base)
+	if _, ok := catalogs[lang]; !ok {
+		return "", false
+	}
+	return lang, true
+}
+
+// FromAcceptLanguage elige el idioma soportado de mayor preferencia de un header
+// Accept-Language (por ejemplo "en-US,en;q=0.9,es;q=0.8"), o Default si no hay ninguno.
+func FromAcceptLanguage(header string) Lang {
+	type option struct {
+		lang    Lang
+		quality float64
+	}
+	var options []option
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		lang, ok := Parse(fields[0])
+		if !ok {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			options = append(options, option{lang, quality})
+		}
+	}
+	if len(options) == 0 {
+		return Default
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].quality > options[j].quality
+	})
+	return options[0].lang
+}
+
+// T devuelve el mensaje key en el idioma l, formateado con args como en fmt.Sprintf.
+// Si el mensaje no está traducido se usa el de Default, y si tampoco existe, la clave.
+func (l Lang) T(key string, args ...interface{}) string {
+	message, ok := catalogs[l][key]
+	if !ok {
+		message, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,210 @@
+package i18n
+
+// catalogs tiene los mensajes de cada idioma. Las claves "label.*" son etiquetas que
+// genera el servidor y las "api.*" sus mensajes de error; el resto son textos de
+// cliente.go. Los encabezados de tabla se traducen completos para mantener el ancho de
+// las columnas.
+var catalogs = map[Lang]map[string]string{
+	Spanish: {
+		"label.race_name":  "GP de %s",
+		"label.last_place": "Último",
+
+		"api.route_not_found":     "Ruta no encontrada",
+		"api.positive_integer":    "El parámetro %s debe ser un entero positivo",
+		"api.invalid_lang":        "El parámetro lang debe ser es o en",
+		"api.limit_too_large":     "El parámetro limit no puede ser mayor que %d",
+		"api.invalid_offset":      "El parámetro offset debe ser un entero mayor o igual a 0",
+		"api.invalid_sort":        "El parámetro sort debe ser uno de: %s",
+		"api.driver_not_found":    "Corredor no encontrado",
+		"api.race_not_found":      "Carrera no encontrada",
+		"api.circuit_not_found":   "Circuito no encontrado",
+		"api.drivers_query":       "Error al consultar los corredores",
+		"api.driver_query":        "Error consultando el corredor",
+		"api.driver_summary":      "Error obteniendo el resumen del piloto",
+		"api.driver_results":      "Error consultando resultados del piloto",
+		"api.races_query":         "Error al consultar las carreras",
+		"api.race_query":          "Error consultando la carrera",
+		"api.podium":              "Error obteniendo el podio",
+		"api.last_place":          "Error obteniendo el último lugar",
+		"api.fastest_lap":         "Error obteniendo vuelta rápida",
+		"api.max_speed":           "Error obteniendo velocidad máxima",
+		"api.laps_led":            "Error obteniendo vueltas lideradas",
+		"api.lead_changes":        "Error obteniendo cambios de líder",
+		"api.race_circuit_record": "Error obteniendo récord del circuito",
+		"api.lap_chart":           "Error consultando vueltas de la carrera",
+		"api.season_winners":      "Error al obtener ganadores",
+		"api.season_fastest_laps": "Error al obtener vueltas rápidas",
+		"api.season_podiums":      "Error al obtener top 3 en podios",
+		"api.season_laps_led":     "Error al obtener vueltas lideradas",
+		"api.circuits_query":      "Error al consultar los circuitos",
+		"api.circuit_query":       "Error consultando el circuito",
+		"api.circuit_races":       "Error consultando carreras del circuito",
+		"api.circuit_lap_record":  "Error obteniendo récord de vuelta",
+
+		"yes":           "Sí",
+		"no":            "No",
+		"error.server":  " Error en la respuesta del servidor: %s",
+		"error.generic": " Error: %v",
+
+		"menu.title":         "===== MENÚ PRINCIPAL =====",
+		"menu.drivers":       "1. Ver todos los corredores",
+		"menu.driver_detail": "2. Ver detalle de un corredor",
+		"menu.races":         "3. Ver todas las carreras",
+		"menu.race_detail":   "4. Ver detalle de una carrera",
+		"menu.season":        "5. Ver resumen de temporada",
+		"menu.exit":          "6. Salir",
+		"menu.prompt":        "Selecciona una opción: ",
+		"menu.invalid":       "❌ Opción inválida. Intenta nuevamente.\n",
+		"menu.unknown":       "❌ Opción no reconocida. Intenta nuevamente.\n",
+		"menu.bye":           "👋 Saliendo del programa...",
+
+		"drivers.loading": "\n🔎 Obteniendo lista de corredores...",
+		"drivers.header":  "\n| # | Nombre   | Apellido     | N Piloto | Equipo            | País |",
+
+		"driver.prompt":         "\nIngrese el numero del piloto: ",
+		"driver.invalid":        "❌ Número de piloto inválido.",
+		"driver.results_header": "| # | Carrera                | Pos Final | Vuelta rápida | Velocidad max | Menor tiempo vuelta     |",
+		"driver.summary_title":  "| Resumen del piloto       |",
+		"driver.wins":           "| Carreras ganadas         | %-4d |",
+		"driver.top3":           "| Veces en el top 3        | %-4d |",
+		"driver.max_speed":      "| Velocidad máxima alcanzada | %s km/h |",
+		"driver.laps_led":       "| Vueltas lideradas        | %-4d |",
+
+		"races.title":       "[3] Ver todas las carreras\n",
+		"races.col.id":      "ID carrera",
+		"races.col.country": "País",
+		"races.col.date":    "Fecha",
+		"races.col.year":    "Year",
+		"races.col.circuit": "Circuito",
+
+		"race.title":            "▶️ [4] Ver detalle de carrera\n",
+		"race.prompt":           "Ingrese el número de la carrera: ",
+		"race.invalid":          "❌ Número de carrera inválido.",
+		"race.header":           "\n Detalle de carrera: %s (%s)\nFecha: %s | Circuito: %s | Año: %d\n",
+		"race.results_title":    "| Resultados                                                    |",
+		"race.results_header":   "| Posicion | Piloto              | Equipo          | Pais      |",
+		"race.fastest_title":    "\n| Vuelta más rápida                                            |",
+		"race.fastest_header":   "| Piloto          | Tiempo Total | Sector 1 | Sector 2 | Sector 3 |",
+		"race.tied":             "Empatada con: %s",
+		"race.max_speed_title":  "\n| Velocidad máxima alcanzada                                   |",
+		"race.max_speed_header": "| Piloto          | Velocidad (km/h)                           |",
+		"race.laps_led_title":   "\n| Vueltas lideradas                                            |",
+		"race.laps_led_header":  "| Piloto          | Vueltas                                    |",
+		"race.lead_changes":     "Cambios de líder: %d",
+		"race.circuit_record":   "\nRécord del circuito: %.3f (%s, %d)",
+		"race.new_record":       "¡Nuevo récord de vuelta del circuito en esta carrera!",
+
+		"season.title":           " [5] Ver resumen de temporada\n",
+		"season.prompt":          "Ingrese la temporada (ej: 2024): ",
+		"season.invalid":         "❌ Temporada inválida.",
+		"season.winners_title":   "\n Top 3 Pilotos con más Victorias - Temporada %d",
+		"season.winners_header":  "| Posición | Piloto           | Equipo         | País | Victorias |",
+		"season.fastest_title":   " Top 3 Pilotos con más Vueltas Rápidas - Temporada %d",
+		"season.fastest_header":  "| Posición | Piloto           | Equipo         | País | Vueltas Rápidas |",
+		"season.podiums_title":   " Top 3 Pilotos con más Podios - Temporada %d",
+		"season.podiums_header":  "| Posición | Piloto           | Equipo         | País | Podios |",
+		"season.laps_led_title":  " Top 3 Pilotos con más Vueltas Lideradas - Temporada %d",
+		"season.laps_led_header": "| Posición | Piloto           | Equipo         | País | Vueltas Lideradas |",
+	},
+	English: {
+		"label.race_name":  "%s GP",
+		"label.last_place": "Last",
+
+		"api.route_not_found":     "Route not found",
+		"api.positive_integer":    "The %s parameter must be a positive integer",
+		"api.invalid_lang":        "The lang parameter must be es or en",
+		"api.limit_too_large":     "The limit parameter cannot be greater than %d",
+		"api.invalid_offset":      "The offset parameter must be an integer greater than or equal to 0",
+		"api.invalid_sort":        "The sort parameter must be one of: %s",
+		"api.driver_not_found":    "Driver not found",
+		"api.race_not_found":      "Race not found",
+		"api.circuit_not_found":   "Circuit not found",
+		"api.drivers_query":       "Error querying drivers",
+		"api.driver_query":        "Error querying the driver",
+		"api.driver_summary":      "Error getting the driver summary",
+		"api.driver_results":      "Error querying the driver results",
+		"api.races_query":         "Error querying races",
+		"api.race_query":          "Error querying the race",
+		"api.podium":              "Error getting the podium",
+		"api.last_place":          "Error getting the last place",
+		"api.fastest_lap":         "Error getting the fastest lap",
+		"api.max_speed":           "Error getting the top speed",
+		"api.laps_led":            "Error getting laps led",
+		"api.lead_changes":        "Error getting lead changes",
+		"api.race_circuit_record": "Error getting the circuit record",
+		"api.lap_chart":           "Error querying the race laps",
+		"api.season_winners":      "Error getting winners",
+		"api.season_fastest_laps": "Error getting fastest laps",
+		"api.season_podiums":      "Error getting top 3 podiums",
+		"api.season_laps_led":     "Error getting laps led",
+		"api.circuits_query":      "Error querying circuits",
+		"api.circuit_query":       "Error querying the circuit",
+		"api.circuit_races":       "Error querying the circuit races",
+		"api.circuit_lap_record":  "Error getting the lap record",
+
+		"yes":           "Yes",
+		"no":            "No",
+		"error.server":  " Server responded with an error: %s",
+		"error.generic": " Error: %v",
+
+		"menu.title":         "===== MAIN MENU =====",
+		"menu.drivers":       "1. View all drivers",
+		"menu.driver_detail": "2. View driver details",
+		"menu.races":         "3. View all races",
+		"menu.race_detail":   "4. View race details",
+		"menu.season":        "5. View season summary",
+		"menu.exit":          "6. Exit",
+		"menu.prompt":        "Choose an option: ",
+		"menu.invalid":       "❌ Invalid option. Please try again.\n",
+		"menu.unknown":       "❌ Unknown option. Please try again.\n",
+		"menu.bye":           "👋 Exiting...",
+
+		"drivers.loading": "\n🔎 Fetching driver list...",
+		"drivers.header":  "\n| # | First    | Last name    | Number   | Team              | Ctry |",
+
+		"driver.prompt":         "\nEnter the driver number: ",
+		"driver.invalid":        "❌ Invalid driver number.",
+		"driver.results_header": "| # | Race                   | Final pos | Fastest lap   | Top speed     | Best lap time           |",
+		"driver.summary_title":  "| Driver summary           |",
+		"driver.wins":           "| Races won                | %-4d |",
+		"driver.top3":           "| Top 3 finishes           | %-4d |",
+		"driver.max_speed":      "| Top speed reached        | %s km/h |",
+		"driver.laps_led":       "| Laps led                 | %-4d |",
+
+		"races.title":       "[3] View all races\n",
+		"races.col.id":      "Race ID",
+		"races.col.country": "Country",
+		"races.col.date":    "Date",
+		"races.col.year":    "Year",
+		"races.col.circuit": "Circuit",
+
+		"race.title":            "▶️ [4] View race details\n",
+		"race.prompt":           "Enter the race number: ",
+		"race.invalid":          "❌ Invalid race number.",
+		"race.header":           "\n Race details: %s (%s)\nDate: %s | Circuit: %s | Year: %d\n",
+		"race.results_title":    "| Results                                                       |",
+		"race.results_header":   "| Position | Driver              | Team            | Country   |",
+		"race.fastest_title":    "\n| Fastest lap                                                  |",
+		"race.fastest_header":   "| Driver          | Total time   | Sector 1 | Sector 2 | Sector 3 |",
+		"race.tied":             "Tied with: %s",
+		"race.max_speed_title":  "\n| Top speed reached                                            |",
+		"race.max_speed_header": "| Driver          | Speed (km/h)                               |",
+		"race.laps_led_title":   "\n| Laps led                                                     |",
+		"race.laps_led_header":  "| Driver          | Laps                                       |",
+		"race.lead_changes":     "Lead changes: %d",
+		"race.circuit_record":   "\nCircuit lap record: %.3f (%s, %d)",
+		"race.new_record":       "New circuit lap record set in this race!",
+
+		"season.title":           " [5] View season summary\n",
+		"season.prompt":          "Enter the season (e.g. 2024): ",
+		"season.invalid":         "❌ Invalid season.",
+		"season.winners_title":   "\n Top 3 Drivers by Wins - Season %d",
+		"season.winners_header":  "| Position | Driver           | Team           | Ctry | Wins      |",
+		"season.fastest_title":   " Top 3 Drivers by Fastest Laps - Season %d",
+		"season.fastest_header":  "| Position | Driver           | Team           | Ctry | Fastest Laps    |",
+		"season.podiums_title":   " Top 3 Drivers by Podiums - Season %d",
+		"season.podiums_header":  "| Position | Driver           | Team           | Ctry | Podiums |",
+		"season.laps_led_title":  " Top 3 Drivers by Laps Led - Season %d",
+		"season.laps_led_header": "| Position | Driver           | Team           | Ctry | Laps Led          |",
+	},
+}
@@ -0,0 +1,339 @@
+// Package ingest carga las posiciones y las vueltas de cada carrera desde OpenF1.
+//
+// Un grupo acotado de workers descarga las sesiones en paralelo (el límite de solicitudes
+// por segundo lo aplica openf1.Client, compartido entre todos) y reparte cada respuesta en
+// lotes. Un único escritor recibe los lotes y los guarda, uno por transacción, para no
+// competir por el bloqueo de escritura de SQLite. Los registros que no pasan la validación
+// de openf1 y los del piloto de reserva se guardan en RejectedRecord (ver SaveRejections).
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"f1_statshub_system/openf1"
+	"f1_statshub_system/storage"
+)
+
+// Options configura la ingesta
+type Options struct {
+	// Workers es la cantidad de descargas simultáneas
+	Workers int
+	// BatchSize es la cantidad de registros por transacción
+	BatchSize int
+	// MaxRetries es la cantidad de intentos por lote si la base está bloqueada
+	MaxRetries int
+
+	// written, si no es nil, se llama desde el escritor después de guardar cada lote con
+	// filas (lo usan los tests)
+	written func(b batch)
+}
+
+// Retry ejecuta operation hasta maxRetries veces mientras falle porque la base está
+// bloqueada, esperando el doble en cada intento. Cualquier otro error se devuelve enseguida.
+func Retry(operation func() error, maxRetries int) error {
+	var err error
+	for i := 0; i < maxRetries; i++ {
+		err = operation()
+		if err == nil {
+			return nil
+		}
+
+		if strings.Contains(err.Error(), "database is locked") {
+			waitTime := time.Duration(math.Pow(2, float64(i))) * 100 * time.Millisecond
+			log.Printf("Intento %d fallido: %v. Esperando %v antes de reintentar...", i+1, err, waitTime)
+			time.Sleep(waitTime)
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("después de %d reintentos: %v", maxRetries, err)
+}
+
+// dataset es un tipo de dato que se descarga por sesión
+type dataset struct {
+	// name se usa en los mensajes de progreso ("posiciones", "vueltas")
+	name string
+	// path es el endpoint de OpenF1, con %d para la session_key
+	path string
+	// rows decodifica la respuesta en las filas a insertar con statements
+	rows func(source string, sessionKey int, raw []json.RawMessage) ([][]interface{}, []openf1.Rejection)
+	// statements son los INSERT que se ejecutan con cada fila
+	statements []string
+}
+
+var datasets = []dataset{
+	// Position queda con la posición de fecha más reciente de cada piloto (la final, al
+	// terminar la ingesta), sin importar el orden en que lleguen los lotes
+	{"posiciones", "/v1/position?session_key=%d", positionRows, []string{
+		`INSERT INTO Position
+		(driver_number, session_key, position, date)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (driver_number, session_key) DO UPDATE SET
+			position = excluded.position,
+			date = excluded.date
+		WHERE excluded.date > Position.date`,
+		`INSERT INTO PositionHistory
+		(driver_number, session_key, position, date)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`,
+	}},
+	{"vueltas", "/v1/laps?session_key=%d", lapRows, []string{
+		`INSERT INTO Laps
+		(driver_number, session_key, lap_number, lap_duration, lap_duration_estimated,
+		 duration_sector_1, duration_sector_2, duration_sector_3,
+		 st_speed, date_start)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`,
+	}},
+}
+
+// job es la descarga de un dataset para una sesión
+type job struct {
+	dataset    *dataset
+	sessionKey int
+}
+
+// batch es una parte de la respuesta de un job. El primer lote lleva los rechazos de la
+// respuesta y el último tiene last en true (si no quedaron filas, es el único y está vacío).
+type batch struct {
+	job      job
+	source   string
+	rows     [][]interface{}
+	rejected []openf1.Rejection
+	from     int
+	total    int
+	last     bool
+}
+
+// Run descarga las posiciones y las vueltas de cada sesión de sessionKeys y las guarda
+// en db. Los errores de una sesión o de un lote se informan y no detienen al resto.
+// Solo devuelve error si se cancela ctx.
+func Run(ctx context.Context, db *storage.DB, api *openf1.Client, sessionKeys []int, opts Options) error {
+	// Configurar la base para mejor manejo de concurrencia (en SQLite, modo WAL)
+	if err := db.BeginBulkLoad(); err != nil {
+		log.Printf("Error configurando la base para la ingesta: %v", err)
+	}
+
+	jobs := make(chan job)
+	batches := make(chan batch, opts.Workers)
+
+	// 1. Workers: descargan, decodifican y reparten en lotes
+	var workers sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for j := range jobs {
+				fetch(ctx, api, j, opts.BatchSize, batches)
+			}
+		}()
+	}
+
+	// 2. Encolar un job por dataset y sesión
+	go func() {
+		defer close(jobs)
+		for _, sessionKey := range sessionKeys {
+			for i := range datasets {
+				select {
+				case jobs <- job{&datasets[i], sessionKey}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	go func() {
+		workers.Wait()
+		close(batches)
+	}()
+
+	// 3. Escritor único: guarda los lotes a medida que llegan
+	processed := make(map[job]int)
+	for b := range batches {
+		if b.from == 0 {
+			err := Retry(func() error { return SaveRejections(db, b.source, b.rejected) }, opts.MaxRetries)
+			if err != nil {
+				log.Printf("Error guardando los registros rechazados de %s: %v", b.source, err)
+			}
+		}
+
+		if len(b.rows) > 0 {
+			err := Retry(func() error {
+				tx, err := db.Begin()
+				if err != nil {
+					return fmt.Errorf("error al iniciar transacción para %s: %v", b.job.dataset.name, err)
+				}
+				if err := insertRows(tx, b.job.dataset.statements, b.rows); err != nil {
+					tx.Rollback()
+					return fmt.Errorf("error insertando %s: %v", b.job.dataset.name, err)
+				}
+				if err := tx.Commit(); err != nil {
+					return fmt.Errorf("error en commit para %s: %v", b.job.dataset.name, err)
+				}
+				return nil
+			}, opts.MaxRetries)
+
+			if err != nil {
+				log.Printf("Error persistente con el lote de %s %d-%d de session_key=%d: %v",
+					b.job.dataset.name, b.from, b.from+len(b.rows), b.job.sessionKey, err)
+			} else {
+				if opts.written != nil {
+					opts.written(b)
+				}
+				processed[b.job] += len(b.rows)
+				fmt.Printf("session_key=%d: procesados %d/%d registros de %s (%.1f%%)\n",
+					b.job.sessionKey, processed[b.job], b.total, b.job.dataset.name,
+					float64(processed[b.job])/float64(b.total)*100)
+			}
+		}
+
+		if b.last {
+			fmt.Printf("Finalizado procesamiento de %s para session_key=%d. Total procesados: %d/%d\n",
+				b.job.dataset.name, b.job.sessionKey, processed[b.job], b.total)
+			delete(processed, b.job)
+		}
+	}
+
+	// Volviendo a configuración inicial
+	if err := db.EndBulkLoad(); err != nil {
+		log.Printf("Error restaurando la configuración de la base después de la ingesta: %v", err)
+	}
+
+	return ctx.Err()
+}
+
+// fetch descarga un job y envía sus filas en lotes de batchSize
+func fetch(ctx context.Context, api *openf1.Client, j job, batchSize int, batches chan<- batch) {
+	source := fmt.Sprintf(j.dataset.path, j.sessionKey)
+	raw, err := api.Get(ctx, source)
+	if err != nil {
+		log.Printf("Error obteniendo %s para session_key=%d: %v", j.dataset.name, j.sessionKey, err)
+		return
+	}
+
+	rows, rejected := j.dataset.rows(source, j.sessionKey, raw)
+	fmt.Printf("session_key=%d: obtenidos %d registros de %s (%d descartados)\n", j.sessionKey, len(raw), j.dataset.name, len(rejected))
+
+	if len(rows) == 0 {
+		batches <- batch{job: j, source: source, rejected: rejected, last: true}
+		return
+	}
+	for i := 0; i < len(rows); i += batchSize {
+		end := i + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		b := batch{job: j, source: source, rows: rows[i:end], from: i, total: len(rows), last: end == len(rows)}
+		if i == 0 {
+			b.rejected = rejected
+		}
+		batches <- b
+	}
+}
+
+// insertRows ejecuta cada sentencia de statements con cada fila de rows dentro de tx
+func insertRows(tx *storage.Tx, statements []string, rows [][]interface{}) error {
+	for _, statement := range statements {
+		stmt, err := tx.Prepare(statement)
+		if err != nil {
+			return fmt.Errorf("error preparando statement: %v", err)
+		}
+		defer stmt.Close()
+
+		for _, row := range rows {
+			if _, err := stmt.Exec(row...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SaveRejections reemplaza los registros rechazados guardados para source por rejected
+// (si rejected está vacío solo borra los anteriores, que ya no aplican)
+func SaveRejections(db *storage.DB, source string, rejected []openf1.Rejection) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM RejectedRecord WHERE source = ?`, source); err != nil {
+		return err
+	}
+	rejectedAt := time.Now().UTC().Format(time.RFC3339)
+	for _, r := range rejected {
+		_, err := tx.Exec(`
+			INSERT INTO RejectedRecord (source, record_index, record, reason, rejected_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, r.Source, r.Index, r.Record, r.Reason, rejectedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// reservedDriver es el número que OpenF1 usa para pilotos de reserva que no están en Driver
+const reservedDriver = 61
+
+// ReservedDriverReason es el motivo con el que quedan en RejectedRecord las posiciones y
+// vueltas de reservedDriver, que no se guardan (verify las cuenta aparte del resto)
+const ReservedDriverReason = "piloto de reserva 61, que no está en Driver"
+
+var errReservedDriver = errors.New(ReservedDriverReason)
+
+func rejectReserved(driverNumber int) error {
+	if driverNumber == reservedDriver {
+		return errReservedDriver
+	}
+	return nil
+}
+
+func positionRows(source string, sessionKey int, raw []json.RawMessage) ([][]interface{}, []openf1.Rejection) {
+	positions, rejected := openf1.Decode(source, raw, func(p openf1.Position) error {
+		return rejectReserved(*p.DriverNumber)
+	})
+
+	rows := make([][]interface{}, 0, len(positions))
+	for _, pos := range positions {
+		rows = append(rows, []interface{}{*pos.DriverNumber, sessionKey, *pos.Position, *pos.Date})
+	}
+	return rows, rejected
+}
+
+// lapRows guarda los campos faltantes como NULL. Si falta lap_duration pero vinieron los
+// tres sectores, se usa su suma y la vuelta queda marcada como estimada; con algún sector
+// faltante no se puede saber el tiempo de la vuelta y lap_duration queda NULL.
+func lapRows(source string, sessionKey int, raw []json.RawMessage) ([][]interface{}, []openf1.Rejection) {
+	laps, rejected := openf1.Decode(source, raw, func(l openf1.Lap) error {
+		return rejectReserved(*l.DriverNumber)
+	})
+
+	rows := make([][]interface{}, 0, len(laps))
+	for _, lap := range laps {
+		// lap_duration_estimated es un entero (0 o 1), igual que en SQLite, en todos los motores
+		lapDuration, estimated := lap.LapDuration, 0
+		if lapDuration == nil && lap.DurationSector1 != nil && lap.DurationSector2 != nil && lap.DurationSector3 != nil {
+			sum := *lap.DurationSector1 + *lap.DurationSector2 + *lap.DurationSector3
+			lapDuration, estimated = &sum, 1
+		}
+
+		// Los punteros nil se guardan como NULL
+		rows = append(rows, []interface{}{
+			*lap.DriverNumber, sessionKey, *lap.LapNumber,
+			lapDuration, estimated, lap.DurationSector1, lap.DurationSector2,
+			lap.DurationSector3, lap.StSpeed, lap.DateStart,
+		})
+	}
+	return rows, rejected
+}
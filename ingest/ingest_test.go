@@ -0,0 +1,312 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"f1_statshub_system/openf1"
+	"f1_statshub_system/stats"
+	"f1_statshub_system/storage"
+	"f1_statshub_system/storage/storagetest"
+)
+
+// openRaces devuelve una base migrada con tres pilotos y las carreras 9472 y 9480, sin
+// posiciones ni vueltas
+func openRaces(t *testing.T) *storage.DB {
+	t.Helper()
+	db := storagetest.Open(t)
+	for _, statement := range []string{
+		`INSERT INTO Driver (driver_number, first_name, last_name, name_acronym, team_name, country_code) VALUES
+		(1, 'Max', 'Verstappen', 'VER', 'Red Bull Racing', 'NED'),
+		(16, 'Charles', 'Leclerc', 'LEC', 'Ferrari', 'MON'),
+		(4, 'Lando', 'Norris', 'NOR', 'McLaren', 'GBR')`,
+		`INSERT INTO Circuit (circuit_key, circuit_short_name, location, country_name, lap_length_km) VALUES
+		(63, 'Sakhir', 'Sakhir', 'Bahrain', 5.412),
+		(149, 'Jeddah', 'Jeddah', 'Saudi Arabia', NULL)`,
+		`INSERT INTO Session (session_key, session_name, session_type, location, country_name, year, circuit_key, circuit_short_name, date_start) VALUES
+		(9472, 'Race', 'Race', 'Sakhir', 'Bahrain', 2024, 63, 'Sakhir', '2024-03-02T15:00:00+00:00'),
+		(9480, 'Race', 'Race', 'Jeddah', 'Saudi Arabia', 2024, 149, 'Jeddah', '2024-03-09T17:00:00+00:00')`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%v\n%s", err, statement)
+		}
+	}
+	return db
+}
+
+// rawRecords codifica records como la respuesta de OpenF1
+func rawRecords(t *testing.T, records ...map[string]interface{}) []json.RawMessage {
+	t.Helper()
+	raw := make([]json.RawMessage, 0, len(records))
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw = append(raw, data)
+	}
+	return raw
+}
+
+// writeBatches guarda rows con las sentencias de d en lotes de batchSize, cada uno en su
+// transacción, como el escritor de Run
+func writeBatches(t *testing.T, db *storage.DB, d *dataset, rows [][]interface{}, batchSize int) {
+	t.Helper()
+	for i := 0; i < len(rows); i += batchSize {
+		end := i + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := insertRows(tx, d.statements, rows[i:end]); err != nil {
+			tx.Rollback()
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestFinalPosition guarda las posiciones de una carrera como lo hace la ingesta y
+// comprueba que Position y DriverSessionResult tengan la posición final de cada piloto,
+// no la primera
+func TestFinalPosition(t *testing.T) {
+	// Verstappen larga primero, Norris pasa a Leclerc y Leclerc termina ganando
+	position := func(driverNumber, position int, date string) map[string]interface{} {
+		return map[string]interface{}{"driver_number": driverNumber, "session_key": 9480, "position": position, "date": date}
+	}
+	chronological := []map[string]interface{}{
+		position(1, 1, "2024-03-09T17:00:00.000000+00:00"),
+		position(16, 2, "2024-03-09T17:00:00.000000+00:00"),
+		position(4, 3, "2024-03-09T17:00:00.000000+00:00"),
+		position(4, 2, "2024-03-09T17:02:30.000000+00:00"),
+		position(16, 3, "2024-03-09T17:02:30.000000+00:00"),
+		position(16, 1, "2024-03-09T17:03:20.000000+00:00"),
+		position(1, 2, "2024-03-09T17:03:20.000000+00:00"),
+		position(4, 3, "2024-03-09T17:03:20.000000+00:00"),
+	}
+	reversed := make([]map[string]interface{}, len(chronological))
+	for i, record := range chronological {
+		reversed[len(chronological)-1-i] = record
+	}
+	want := map[int]int{16: 1, 1: 2, 4: 3}
+
+	tests := []struct {
+		name      string
+		records   []map[string]interface{}
+		batchSize int
+	}{
+		{"en orden, un lote", chronological, 100},
+		{"en orden, lotes de 2", chronological, 2},
+		{"en orden inverso", reversed, 3},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db := openRaces(t)
+			positions := &datasets[0]
+			source := fmt.Sprintf(positions.path, 9480)
+			rows, rejected := positions.rows(source, 9480, rawRecords(t, tc.records...))
+			if len(rejected) > 0 {
+				t.Fatalf("rechazados: %+v", rejected)
+			}
+			writeBatches(t, db, positions, rows, tc.batchSize)
+			if err := stats.Rebuild(db); err != nil {
+				t.Fatal(err)
+			}
+
+			for driverNumber, wantPosition := range want {
+				var stored, result int
+				err := db.QueryRow(db.Rebind(`SELECT position FROM Position WHERE driver_number = ? AND session_key = 9480`), driverNumber).Scan(&stored)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = db.QueryRow(db.Rebind(`SELECT position FROM DriverSessionResult WHERE driver_number = ? AND session_key = 9480`), driverNumber).Scan(&result)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if stored != wantPosition || result != wantPosition {
+					t.Errorf("piloto %d: Position %d y DriverSessionResult %d, se esperaba %d", driverNumber, stored, result, wantPosition)
+				}
+			}
+
+			var history int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM PositionHistory`).Scan(&history); err != nil {
+				t.Fatal(err)
+			}
+			if history != len(tc.records) {
+				t.Errorf("PositionHistory tiene %d filas, se esperaban %d", history, len(tc.records))
+			}
+		})
+	}
+}
+
+// fakeOpenF1 responde /v1/position y /v1/laps de las carreras 9472 y 9480 con tres
+// muestras de posición y cinco vueltas por piloto, más un registro del piloto de reserva
+// y uno inválido en cada respuesta. Cualquier otra carrera responde 404.
+func fakeOpenF1(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionKey, _ := strconv.Atoi(r.URL.Query().Get("session_key"))
+		start, ok := map[int]time.Time{
+			9472: time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC),
+			9480: time.Date(2024, 3, 9, 17, 0, 0, 0, time.UTC),
+		}[sessionKey]
+		if !ok {
+			http.Error(w, `{"detail": "Not Found"}`, 404)
+			return
+		}
+		date := func(d time.Duration) string { return start.Add(d).Format(time.RFC3339) }
+
+		var records []map[string]interface{}
+		switch r.URL.Path {
+		case "/v1/position":
+			for sample := 0; sample < 3; sample++ {
+				for i, driverNumber := range []int{1, 16, 4} {
+					records = append(records, map[string]interface{}{
+						"driver_number": driverNumber, "session_key": sessionKey,
+						"position": (i+sample)%3 + 1, "date": date(time.Duration(sample) * time.Minute),
+					})
+				}
+			}
+			records = append(records,
+				map[string]interface{}{"driver_number": 61, "session_key": sessionKey, "position": 20, "date": date(0)},
+				map[string]interface{}{"driver_number": 1, "session_key": sessionKey, "position": nil, "date": date(time.Hour)},
+			)
+		case "/v1/laps":
+			for _, driverNumber := range []int{1, 16, 4} {
+				for lap := 1; lap <= 5; lap++ {
+					records = append(records, map[string]interface{}{
+						"driver_number": driverNumber, "session_key": sessionKey, "lap_number": lap,
+						"lap_duration": 90.0 + float64(driverNumber)/10, "date_start": date(time.Duration(lap-1) * 90 * time.Second),
+					})
+				}
+			}
+			records = append(records,
+				map[string]interface{}{"driver_number": 61, "session_key": sessionKey, "lap_number": 1, "lap_duration": 95.0},
+				map[string]interface{}{"driver_number": 4, "session_key": sessionKey, "lap_number": nil},
+			)
+		default:
+			http.Error(w, `{"detail": "Not Found"}`, 404)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(records)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRun(t *testing.T) {
+	db := openRaces(t)
+	api := openf1.New(fakeOpenF1(t).URL, 0, 1)
+
+	// El escritor es uno solo: written nunca se ejecuta en paralelo consigo mismo
+	var mu sync.Mutex
+	var active, maxActive int
+	batches := make(map[string][]batch)
+	opts := Options{
+		Workers:    3,
+		BatchSize:  4,
+		MaxRetries: 2,
+		written: func(b batch) {
+			mu.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			key := fmt.Sprintf("%s %d", b.job.dataset.name, b.job.sessionKey)
+			batches[key] = append(batches[key], b)
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+		},
+	}
+
+	// 9999 no existe en OpenF1: se informa y no detiene al resto
+	if err := Run(context.Background(), db, api, []int{9472, 9480, 9999}, opts); err != nil {
+		t.Fatal(err)
+	}
+	if maxActive != 1 {
+		t.Errorf("%d lotes se guardaron a la vez, se esperaba 1", maxActive)
+	}
+
+	// Cada lote tiene BatchSize filas salvo el último, y los lotes de un job llegan en orden
+	wantRows := map[string]int{
+		"posiciones 9472": 9, "posiciones 9480": 9,
+		"vueltas 9472": 15, "vueltas 9480": 15,
+	}
+	if len(batches) != len(wantRows) {
+		t.Errorf("se guardaron lotes de %d jobs, se esperaban %d", len(batches), len(wantRows))
+	}
+	for key, want := range wantRows {
+		rows := 0
+		for i, b := range batches[key] {
+			if b.from != rows || b.total != want || b.last != (i == len(batches[key])-1) {
+				t.Errorf("%s: lote %d con from %d, total %d y last %v", key, i, b.from, b.total, b.last)
+			}
+			if len(b.rows) > opts.BatchSize || (!b.last && len(b.rows) != opts.BatchSize) {
+				t.Errorf("%s: lote %d de %d filas con BatchSize %d", key, i, len(b.rows), opts.BatchSize)
+			}
+			rows += len(b.rows)
+		}
+		if rows != want {
+			t.Errorf("%s: %d filas guardadas, se esperaban %d", key, rows, want)
+		}
+	}
+
+	counts := []struct {
+		query string
+		want  int
+	}{
+		{`SELECT COUNT(*) FROM PositionHistory WHERE session_key = 9472`, 9},
+		{`SELECT COUNT(*) FROM PositionHistory WHERE session_key = 9480`, 9},
+		{`SELECT COUNT(*) FROM Position WHERE session_key = 9472`, 3},
+		{`SELECT COUNT(*) FROM Position WHERE session_key = 9480`, 3},
+		{`SELECT COUNT(*) FROM Laps WHERE session_key = 9472`, 15},
+		{`SELECT COUNT(*) FROM Laps WHERE session_key = 9480`, 15},
+		{`SELECT COUNT(*) FROM Laps WHERE driver_number = 61`, 0},
+	}
+	for _, c := range counts {
+		var got int
+		if err := db.QueryRow(c.query).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("%s = %d, se esperaba %d", c.query, got, c.want)
+		}
+	}
+
+	// Los rechazos de cada respuesta quedan en RejectedRecord con su fuente y su motivo
+	for _, source := range []string{
+		"/v1/position?session_key=9472", "/v1/position?session_key=9480",
+		"/v1/laps?session_key=9472", "/v1/laps?session_key=9480",
+	} {
+		var total, reserved int
+		err := db.QueryRow(db.Rebind(`
+			SELECT COUNT(*), COUNT(CASE WHEN reason = ? THEN 1 END)
+			FROM RejectedRecord
+			WHERE source = ?
+		`), ReservedDriverReason, source).Scan(&total, &reserved)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 2 || reserved != 1 {
+			t.Errorf("%s: %d rechazos (%d del piloto de reserva), se esperaban 2 (1)", source, total, reserved)
+		}
+	}
+}
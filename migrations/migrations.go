@@ -0,0 +1,191 @@
+// Package migrations mantiene el esquema de la base con migraciones numeradas. Cada
+// migración es un par de archivos en sql/<motor>/ (NNNN_nombre.up.sql y NNNN_nombre.down.sql)
+// embebidos en el binario; las versiones aplicadas se registran en schema_version.
+//
+// Para cambiar el esquema se agrega un par nuevo con el número siguiente en el directorio
+// de cada motor (sqlite y postgres); nunca se edita una migración ya publicada, porque
+// las bases existentes no la volverían a aplicar.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"f1_statshub_system/storage"
+)
+
+//go:embed sql/*/*.sql
+var files embed.FS
+
+// Migration es una migración numerada con sus sentencias para aplicarla y revertirla
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+const createSchemaVersion = `
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`
+
+// All devuelve las migraciones embebidas del motor backend ordenadas por versión
+func All(backend string) ([]Migration, error) {
+	dir := path.Join("sql", backend)
+	entries, err := files.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("no hay migraciones para el motor %s", backend)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migración con nombre inválido: %s", fileName)
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		number, name, found := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !found || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migración con nombre inválido: %s", fileName)
+		}
+
+		content, err := files.ReadFile(path.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("la migración %d tiene dos nombres: %s y %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("a la migración %d le falta el archivo up o down", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Current devuelve la versión del esquema de db (0 si no tiene migraciones aplicadas).
+// Crea schema_version si no existe.
+func Current(db *storage.DB) (int, error) {
+	if _, err := db.Exec(createSchemaVersion); err != nil {
+		return 0, err
+	}
+	return Version(db)
+}
+
+// Version es Current sin escribir en la base, para las conexiones de solo lectura: si
+// schema_version no existe la base no tiene el esquema de este programa y es un error
+func Version(db *storage.DB) (int, error) {
+	var version int
+	err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("no se pudo leer schema_version (¿la base tiene las migraciones aplicadas?): %v", err)
+	}
+	return version, nil
+}
+
+// Up aplica en orden todas las migraciones pendientes y devuelve la versión final
+func Up(db *storage.DB) (int, error) {
+	migrations, err := All(db.Name())
+	if err != nil {
+		return 0, err
+	}
+	current, err := Current(db)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		appliedAt := time.Now().UTC().Format(time.RFC3339)
+		err := inTx(db, m.Up, `INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`, m.Version, m.Name, appliedAt)
+		if err != nil {
+			return current, fmt.Errorf("error aplicando la migración %04d_%s: %v", m.Version, m.Name, err)
+		}
+		current = m.Version
+	}
+	return current, nil
+}
+
+// Down revierte las últimas steps migraciones aplicadas y devuelve la versión final
+func Down(db *storage.DB, steps int) (int, error) {
+	migrations, err := All(db.Name())
+	if err != nil {
+		return 0, err
+	}
+	current, err := Current(db)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := migrations[i]
+		if m.Version > current {
+			continue
+		}
+		err := inTx(db, m.Down, `DELETE FROM schema_version WHERE version = ?`, m.Version)
+		if err != nil {
+			return current, fmt.Errorf("error revirtiendo la migración %04d_%s: %v", m.Version, m.Name, err)
+		}
+		steps--
+		current, err = Current(db)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return current, nil
+}
+
+// inTx ejecuta las sentencias de una migración y el registro en schema_version en una
+// sola transacción, para que una migración fallida no quede aplicada a medias
+func inTx(db *storage.DB, statements, record string, args ...interface{}) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(statements); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
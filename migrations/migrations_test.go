@@ -0,0 +1,160 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"f1_statshub_system/migrations"
+	"f1_statshub_system/storage"
+	"f1_statshub_system/storage/storagetest"
+)
+
+// TestEnginesInSync comprueba que cada motor tenga las mismas migraciones, con el mismo
+// número y nombre
+func TestEnginesInSync(t *testing.T) {
+	sqlite, err := migrations.All("sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	postgres, err := migrations.All("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sqlite) != len(postgres) {
+		t.Fatalf("sqlite tiene %d migraciones y postgres %d", len(sqlite), len(postgres))
+	}
+	for i := range sqlite {
+		if sqlite[i].Version != postgres[i].Version || sqlite[i].Name != postgres[i].Name {
+			t.Errorf("migración %d: sqlite %04d_%s, postgres %04d_%s", i, sqlite[i].Version, sqlite[i].Name, postgres[i].Version, postgres[i].Name)
+		}
+	}
+}
+
+// TestUpDown revierte todas las migraciones y las vuelve a aplicar, con SQLite en memoria
+// o, si F1_TEST_POSTGRES_DSN está definida, en un esquema nuevo de esa base PostgreSQL
+func TestUpDown(t *testing.T) {
+	db := storagetest.Open(t)
+	all, err := migrations.All(db.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := all[len(all)-1].Version
+
+	if current, err := migrations.Current(db); err != nil || current != latest {
+		t.Fatalf("Current después de Open = %d, %v; se esperaba %d", current, err, latest)
+	}
+
+	// De a una, para que cada down se pruebe contra el esquema que deja el anterior
+	for i := len(all) - 1; i >= 0; i-- {
+		current, err := migrations.Down(db, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := 0
+		if i > 0 {
+			want = all[i-1].Version
+		}
+		if current != want {
+			t.Fatalf("Down dejó la versión %d, se esperaba %d", current, want)
+		}
+	}
+	if current, err := migrations.Down(db, 1); err != nil || current != 0 {
+		t.Fatalf("Down sin migraciones aplicadas = %d, %v; se esperaba 0", current, err)
+	}
+
+	if current, err := migrations.Up(db); err != nil || current != latest {
+		t.Fatalf("Up = %d, %v; se esperaba %d", current, err, latest)
+	}
+	storagetest.Seed(t, db)
+}
+
+// TestVersion comprueba que Version falle sin schema_version en lugar de crearla
+func TestVersion(t *testing.T) {
+	empty, err := storage.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer empty.Close()
+	empty.SetMaxOpenConns(1)
+
+	if version, err := migrations.Version(empty); err == nil {
+		t.Errorf("Version sin schema_version = %d, se esperaba un error", version)
+	}
+	var tables int
+	if err := empty.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_version'`).Scan(&tables); err != nil || tables != 0 {
+		t.Errorf("Version creó schema_version (%d, %v)", tables, err)
+	}
+
+	db := storagetest.Open(t)
+	all, err := migrations.All(db.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, err := migrations.Version(db); err != nil || version != all[len(all)-1].Version {
+		t.Errorf("Version = %d, %v; se esperaba %d", version, err, all[len(all)-1].Version)
+	}
+}
+
+// TestFinalPosition comprueba que 0007 reemplace en Position la primera posición de cada
+// piloto por la última de PositionHistory
+func TestFinalPosition(t *testing.T) {
+	db := storagetest.Open(t)
+	latest, err := migrations.Current(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrations.Down(db, latest-6); err != nil {
+		t.Fatal(err)
+	}
+	if version, err := migrations.Current(db); err != nil || version != 6 {
+		t.Fatalf("Current = %d, %v; se esperaba 6", version, err)
+	}
+
+	// Con el esquema 6 la ingesta dejaba en Position el primer registro de cada piloto.
+	// Norris no tiene PositionHistory y conserva su fila.
+	for _, statement := range []string{
+		`INSERT INTO Driver (driver_number, first_name, last_name, name_acronym, team_name, country_code) VALUES
+		(1, 'Max', 'Verstappen', 'VER', 'Red Bull Racing', 'NED'),
+		(16, 'Charles', 'Leclerc', 'LEC', 'Ferrari', 'MON'),
+		(4, 'Lando', 'Norris', 'NOR', 'McLaren', 'GBR')`,
+		`INSERT INTO Circuit (circuit_key, circuit_short_name, location, country_name, lap_length_km) VALUES
+		(149, 'Jeddah', 'Jeddah', 'Saudi Arabia', NULL)`,
+		`INSERT INTO Session (session_key, session_name, session_type, location, country_name, year, circuit_key, circuit_short_name, date_start) VALUES
+		(9480, 'Race', 'Race', 'Jeddah', 'Saudi Arabia', 2024, 149, 'Jeddah', '2024-03-09T17:00:00+00:00')`,
+		`INSERT INTO PositionHistory (driver_number, session_key, position, date) VALUES
+		(1, 9480, 1, '2024-03-09T17:00:00.000000+00:00'),
+		(16, 9480, 2, '2024-03-09T17:00:00.000000+00:00'),
+		(16, 9480, 1, '2024-03-09T17:03:20.000000+00:00'),
+		(1, 9480, 2, '2024-03-09T17:03:20.000000+00:00')`,
+		`INSERT INTO Position (driver_number, session_key, position, date) VALUES
+		(1, 9480, 1, '2024-03-09T17:00:00.000000+00:00'),
+		(16, 9480, 2, '2024-03-09T17:00:00.000000+00:00'),
+		(4, 9480, 3, '2024-03-09T17:00:00.000000+00:00')`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%v\n%s", err, statement)
+		}
+	}
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]struct {
+		position int
+		date     string
+	}{
+		16: {1, "2024-03-09T17:03:20.000000+00:00"},
+		1:  {2, "2024-03-09T17:03:20.000000+00:00"},
+		4:  {3, "2024-03-09T17:00:00.000000+00:00"},
+	}
+	for driverNumber, w := range want {
+		var position int
+		var date string
+		err := db.QueryRow(db.Rebind(`SELECT position, date FROM Position WHERE driver_number = ? AND session_key = 9480`), driverNumber).Scan(&position, &date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if position != w.position || date != w.date {
+			t.Errorf("piloto %d: posición %d (%s), se esperaba %d (%s)", driverNumber, position, date, w.position, w.date)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+-- Esquema inicial para PostgreSQL: las mismas tablas, índices y vista que la versión
+-- de SQLite, con DOUBLE PRECISION en lugar de REAL (que en PostgreSQL tiene 4 bytes).
+--
+-- Las claves foráneas se dejan fuera porque SQLite no las verifica (foreign_keys está
+-- desactivado) y la ingesta depende de eso: guarda posiciones y vueltas de pilotos que
+-- no están en Driver. Declararlas acá haría fallar lotes completos que en SQLite entran.
+
+-- Tabla Driver
+CREATE TABLE IF NOT EXISTS Driver (
+	driver_number INTEGER PRIMARY KEY,
+	first_name TEXT NOT NULL,
+	last_name TEXT NOT NULL,
+	name_acronym TEXT NOT NULL,
+	team_name TEXT NOT NULL,
+	country_code TEXT NOT NULL
+);
+
+-- Tabla Session
+CREATE TABLE IF NOT EXISTS Session (
+	session_key INTEGER PRIMARY KEY,
+	session_name TEXT NOT NULL,
+	session_type TEXT NOT NULL,
+	location TEXT NOT NULL,
+	country_name TEXT NOT NULL,
+	year INTEGER NOT NULL,
+	circuit_short_name TEXT NOT NULL,
+	date_start TEXT NOT NULL
+);
+
+-- Tabla Circuit
+CREATE TABLE IF NOT EXISTS Circuit (
+	circuit_key INTEGER PRIMARY KEY,
+	circuit_short_name TEXT NOT NULL UNIQUE,
+	location TEXT NOT NULL,
+	country_name TEXT NOT NULL,
+	lap_length_km DOUBLE PRECISION
+);
+
+-- Tabla Position
+CREATE TABLE IF NOT EXISTS Position (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	position INTEGER NOT NULL,
+	date TEXT NOT NULL,
+	PRIMARY KEY (driver_number, session_key)
+);
+
+-- Tabla PositionHistory (todas las posiciones reportadas durante la carrera)
+CREATE TABLE IF NOT EXISTS PositionHistory (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	position INTEGER NOT NULL,
+	date TEXT NOT NULL,
+	PRIMARY KEY (session_key, driver_number, date)
+);
+
+-- Tabla Laps
+CREATE TABLE IF NOT EXISTS Laps (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration DOUBLE PRECISION NOT NULL,
+	duration_sector_1 DOUBLE PRECISION NOT NULL,
+	duration_sector_2 DOUBLE PRECISION NOT NULL,
+	duration_sector_3 DOUBLE PRECISION NOT NULL,
+	st_speed DOUBLE PRECISION NOT NULL,
+	date_start TEXT NOT NULL,
+	PRIMARY KEY (driver_number, session_key, lap_number)
+);
+
+-- Tabla FastestLap (vuelta rápida de cada carrera; en caso de empate hay una fila por vuelta empatada)
+CREATE TABLE IF NOT EXISTS FastestLap (
+	session_key INTEGER NOT NULL,
+	driver_number INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration DOUBLE PRECISION NOT NULL,
+	duration_sector_1 DOUBLE PRECISION,
+	duration_sector_2 DOUBLE PRECISION,
+	duration_sector_3 DOUBLE PRECISION,
+	date_start TEXT NOT NULL,
+	PRIMARY KEY (session_key, driver_number, lap_number)
+);
+
+-- Tabla CircuitLapRecord (vuelta más rápida en carrera registrada en cada circuito)
+CREATE TABLE IF NOT EXISTS CircuitLapRecord (
+	circuit_key INTEGER PRIMARY KEY,
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration DOUBLE PRECISION NOT NULL
+);
+
+-- Tabla DriverSessionResult (estadísticas precalculadas de cada piloto en cada
+-- carrera; position es NULL si el piloto tiene vueltas pero no posición final)
+CREATE TABLE IF NOT EXISTS DriverSessionResult (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	year INTEGER NOT NULL,
+	position INTEGER,
+	best_lap_duration DOUBLE PRECISION,
+	max_speed DOUBLE PRECISION,
+	fastest_lap INTEGER NOT NULL,
+	laps_led INTEGER NOT NULL,
+	PRIMARY KEY (driver_number, session_key)
+);
+
+-- Tabla DriverSeasonStats (totales precalculados de cada piloto por temporada)
+CREATE TABLE IF NOT EXISTS DriverSeasonStats (
+	driver_number INTEGER NOT NULL,
+	year INTEGER NOT NULL,
+	races INTEGER NOT NULL,
+	wins INTEGER NOT NULL,
+	podiums INTEGER NOT NULL,
+	fastest_laps INTEGER NOT NULL,
+	laps_led INTEGER NOT NULL,
+	max_speed DOUBLE PRECISION,
+	PRIMARY KEY (driver_number, year)
+);
+
+-- Índices secundarios para las consultas de los handlers (las búsquedas por
+-- driver_number en Laps ya usan la clave primaria)
+CREATE INDEX IF NOT EXISTS idx_laps_session_duration ON Laps (session_key, lap_duration);
+CREATE INDEX IF NOT EXISTS idx_laps_session_speed ON Laps (session_key, st_speed);
+CREATE INDEX IF NOT EXISTS idx_position_session ON Position (session_key, position);
+CREATE INDEX IF NOT EXISTS idx_session_circuit ON Session (circuit_short_name, date_start);
+CREATE INDEX IF NOT EXISTS idx_session_year ON Session (year, date_start);
+CREATE INDEX IF NOT EXISTS idx_driver_season_stats_year ON DriverSeasonStats (year);
+
+-- Vista LapPosition: posición de cada piloto al terminar cada vuelta,
+-- tomando el último registro de PositionHistory antes del fin de la vuelta
+CREATE OR REPLACE VIEW LapPosition AS
+SELECT
+	l.session_key,
+	l.driver_number,
+	l.lap_number,
+	l.lap_duration,
+	(
+		SELECT ph.position
+		FROM PositionHistory ph
+		WHERE ph.session_key = l.session_key
+		AND ph.driver_number = l.driver_number
+		AND ph.date::timestamptz <= l.date_start::timestamptz + l.lap_duration * interval '1 second'
+		ORDER BY ph.date DESC
+		LIMIT 1
+	) AS position
+FROM Laps l;
@@ -0,0 +1,2 @@
+-- Elimina la tabla de registros rechazados
+DROP TABLE IF EXISTS RejectedRecord;
@@ -0,0 +1,43 @@
+-- Vuelve a las columnas NOT NULL de Laps y FastestLap: los NULL se guardan otra vez como 0
+-- y las fechas faltantes como el comienzo de la carrera. lap_duration_estimated se pierde.
+
+DROP VIEW IF EXISTS LapPosition;
+
+UPDATE Laps SET
+	lap_duration = COALESCE(lap_duration, 0),
+	duration_sector_1 = COALESCE(duration_sector_1, 0),
+	duration_sector_2 = COALESCE(duration_sector_2, 0),
+	duration_sector_3 = COALESCE(duration_sector_3, 0),
+	st_speed = COALESCE(st_speed, 0),
+	date_start = COALESCE(date_start, (SELECT s.date_start FROM Session s WHERE s.session_key = Laps.session_key), '');
+
+ALTER TABLE Laps
+	ALTER COLUMN lap_duration SET NOT NULL,
+	ALTER COLUMN duration_sector_1 SET NOT NULL,
+	ALTER COLUMN duration_sector_2 SET NOT NULL,
+	ALTER COLUMN duration_sector_3 SET NOT NULL,
+	ALTER COLUMN st_speed SET NOT NULL,
+	ALTER COLUMN date_start SET NOT NULL,
+	DROP COLUMN lap_duration_estimated;
+
+UPDATE FastestLap SET
+	date_start = COALESCE(date_start, (SELECT s.date_start FROM Session s WHERE s.session_key = FastestLap.session_key), '');
+
+ALTER TABLE FastestLap ALTER COLUMN date_start SET NOT NULL;
+
+CREATE VIEW LapPosition AS
+SELECT
+	l.session_key,
+	l.driver_number,
+	l.lap_number,
+	l.lap_duration,
+	(
+		SELECT ph.position
+		FROM PositionHistory ph
+		WHERE ph.session_key = l.session_key
+		AND ph.driver_number = l.driver_number
+		AND ph.date::timestamptz <= l.date_start::timestamptz + l.lap_duration * interval '1 second'
+		ORDER BY ph.date DESC
+		LIMIT 1
+	) AS position
+FROM Laps l;
@@ -0,0 +1,74 @@
+-- Las columnas de Laps que OpenF1 puede mandar en null pasan a admitir NULL, en lugar de
+-- guardar 0 o datos inventados, y lap_duration_estimated marca las vueltas cuyo
+-- lap_duration se calculó sumando los tres sectores. FastestLap.date_start también admite
+-- NULL. La vista LapPosition se recrea para usar la nueva columna.
+
+DROP VIEW IF EXISTS LapPosition;
+
+ALTER TABLE Laps
+	ALTER COLUMN lap_duration DROP NOT NULL,
+	ALTER COLUMN duration_sector_1 DROP NOT NULL,
+	ALTER COLUMN duration_sector_2 DROP NOT NULL,
+	ALTER COLUMN duration_sector_3 DROP NOT NULL,
+	ALTER COLUMN st_speed DROP NOT NULL,
+	ALTER COLUMN date_start DROP NOT NULL,
+	ADD COLUMN lap_duration_estimated INTEGER NOT NULL DEFAULT 0;
+
+-- Los 0 que se guardaban por datos faltantes pasan a NULL. Un lap_duration igual a la suma
+-- de los sectores disponibles cuando falta alguno no es una vuelta completa y también pasa
+-- a NULL. Una fecha de inicio más de un día posterior al comienzo de la carrera es la hora
+-- de la ingesta, que se guardaba cuando faltaba date_start. Las vueltas ya guardadas con
+-- lap_duration estimado a partir de los tres sectores no se distinguen de las informadas.
+UPDATE Laps SET
+	lap_duration = CASE
+		WHEN lap_duration <= 0 THEN NULL
+		WHEN (duration_sector_1 <= 0 OR duration_sector_2 <= 0 OR duration_sector_3 <= 0)
+			AND ABS(lap_duration - (duration_sector_1 + duration_sector_2 + duration_sector_3)) <= 0.001
+			THEN NULL
+		ELSE lap_duration
+	END,
+	duration_sector_1 = NULLIF(duration_sector_1, 0),
+	duration_sector_2 = NULLIF(duration_sector_2, 0),
+	duration_sector_3 = NULLIF(duration_sector_3, 0),
+	st_speed = NULLIF(st_speed, 0),
+	date_start = CASE
+		WHEN date_start::timestamptz > (
+			SELECT s.date_start::timestamptz + interval '1 day'
+			FROM Session s
+			WHERE s.session_key = Laps.session_key
+		) THEN NULL
+		ELSE date_start
+	END;
+
+ALTER TABLE FastestLap ALTER COLUMN date_start DROP NOT NULL;
+
+-- Vista LapPosition: posición de cada piloto al terminar cada vuelta, tomando el último
+-- registro de PositionHistory antes del fin de la vuelta. Si falta date_start o
+-- lap_duration, el fin de la vuelta es el comienzo de la siguiente vuelta del piloto; si
+-- tampoco se conoce, position queda NULL.
+CREATE VIEW LapPosition AS
+SELECT
+	l.session_key,
+	l.driver_number,
+	l.lap_number,
+	l.lap_duration,
+	l.lap_duration_estimated,
+	(
+		SELECT ph.position
+		FROM PositionHistory ph
+		WHERE ph.session_key = l.session_key
+		AND ph.driver_number = l.driver_number
+		AND ph.date::timestamptz <= COALESCE(
+			l.date_start::timestamptz + l.lap_duration * interval '1 second',
+			(
+				SELECT n.date_start::timestamptz
+				FROM Laps n
+				WHERE n.session_key = l.session_key
+				AND n.driver_number = l.driver_number
+				AND n.lap_number = l.lap_number + 1
+			)
+		)
+		ORDER BY ph.date DESC
+		LIMIT 1
+	) AS position
+FROM Laps l;
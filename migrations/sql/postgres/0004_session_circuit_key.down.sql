@@ -0,0 +1,13 @@
+-- Vuelve a unir las carreras con Circuit por circuit_short_name. Si hay circuitos con el
+-- mismo nombre se conserva el de menor circuit_key.
+
+DROP INDEX IF EXISTS idx_session_circuit_key;
+ALTER TABLE Session DROP COLUMN circuit_key;
+CREATE INDEX IF NOT EXISTS idx_session_circuit ON Session (circuit_short_name, date_start);
+
+DELETE FROM Circuit a
+USING Circuit b
+WHERE a.circuit_short_name = b.circuit_short_name
+AND a.circuit_key > b.circuit_key;
+
+ALTER TABLE Circuit ADD CONSTRAINT circuit_circuit_short_name_key UNIQUE (circuit_short_name);
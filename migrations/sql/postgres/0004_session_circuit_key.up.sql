@@ -0,0 +1,19 @@
+-- Session guarda el circuit_key de OpenF1 y las carreras se unen con Circuit por esa
+-- columna en lugar de por circuit_short_name, que puede cambiar entre temporadas o
+-- repetirse entre circuitos. Por lo mismo circuit_short_name deja de ser UNIQUE en
+-- Circuit: con la restricción, el segundo circuito con el mismo nombre no se guardaba.
+
+ALTER TABLE Circuit DROP CONSTRAINT IF EXISTS circuit_circuit_short_name_key;
+
+-- Las carreras ya guardadas toman el circuit_key del circuito con su nombre; si no hay
+-- ninguno queda NULL hasta que la próxima ingesta lo complete
+ALTER TABLE Session ADD COLUMN circuit_key INTEGER;
+
+UPDATE Session SET circuit_key = (
+	SELECT c.circuit_key
+	FROM Circuit c
+	WHERE c.circuit_short_name = Session.circuit_short_name
+);
+
+DROP INDEX IF EXISTS idx_session_circuit;
+CREATE INDEX idx_session_circuit_key ON Session (circuit_key, date_start);
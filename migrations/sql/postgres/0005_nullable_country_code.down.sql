@@ -0,0 +1,4 @@
+-- Vuelve a la columna country_code NOT NULL: los pilotos sin país se guardan con ''.
+
+UPDATE Driver SET country_code = '' WHERE country_code IS NULL;
+ALTER TABLE Driver ALTER COLUMN country_code SET NOT NULL;
@@ -0,0 +1,4 @@
+-- Driver.country_code admite NULL: OpenF1 no informa el país de algunos pilotos (reservas,
+-- pilotos de prácticas) y esos registros se descartaban enteros.
+
+ALTER TABLE Driver ALTER COLUMN country_code DROP NOT NULL;
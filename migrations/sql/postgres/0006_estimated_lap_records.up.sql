@@ -0,0 +1,25 @@
+-- Las vueltas con lap_duration estimado (la suma de los tres sectores) dejan de contar
+-- como vuelta rápida y como récord de circuito. Se borran las que quedaron guardadas: la
+-- próxima ingesta recalcula FastestLap y vuelve a elegir el récord de cada circuito entre
+-- las vueltas informadas (CircuitLapRecord solo se reemplaza por una vuelta mejor, así que
+-- un récord estimado no se corregiría solo).
+
+DELETE FROM FastestLap
+WHERE EXISTS (
+	SELECT 1
+	FROM Laps l
+	WHERE l.session_key = FastestLap.session_key
+	AND l.driver_number = FastestLap.driver_number
+	AND l.lap_number = FastestLap.lap_number
+	AND l.lap_duration_estimated = 1
+);
+
+DELETE FROM CircuitLapRecord
+WHERE EXISTS (
+	SELECT 1
+	FROM Laps l
+	WHERE l.session_key = CircuitLapRecord.session_key
+	AND l.driver_number = CircuitLapRecord.driver_number
+	AND l.lap_number = CircuitLapRecord.lap_number
+	AND l.lap_duration_estimated = 1
+);
@@ -0,0 +1,2 @@
+-- Las primeras posiciones reemplazadas no se restauran: siguen en PositionHistory, pero
+-- la posición final es la correcta también para el esquema anterior.
@@ -0,0 +1,32 @@
+DROP VIEW IF EXISTS LapPosition;
+
+-- Vista LapPosition: posición de cada piloto al terminar cada vuelta, tomando el último
+-- registro de PositionHistory antes del fin de la vuelta. Si falta date_start o
+-- lap_duration, el fin de la vuelta es el comienzo de la siguiente vuelta del piloto; si
+-- tampoco se conoce, position queda NULL.
+CREATE VIEW LapPosition AS
+SELECT
+	l.session_key,
+	l.driver_number,
+	l.lap_number,
+	l.lap_duration,
+	l.lap_duration_estimated,
+	(
+		SELECT ph.position
+		FROM PositionHistory ph
+		WHERE ph.session_key = l.session_key
+		AND ph.driver_number = l.driver_number
+		AND ph.date::timestamptz <= COALESCE(
+			l.date_start::timestamptz + l.lap_duration * interval '1 second',
+			(
+				SELECT n.date_start::timestamptz
+				FROM Laps n
+				WHERE n.session_key = l.session_key
+				AND n.driver_number = l.driver_number
+				AND n.lap_number = l.lap_number + 1
+			)
+		)
+		ORDER BY ph.date DESC
+		LIMIT 1
+	) AS position
+FROM Laps l;
@@ -0,0 +1,12 @@
+-- Elimina el esquema inicial completo (los índices se eliminan con sus tablas)
+DROP VIEW IF EXISTS LapPosition;
+DROP TABLE IF EXISTS DriverSeasonStats;
+DROP TABLE IF EXISTS DriverSessionResult;
+DROP TABLE IF EXISTS CircuitLapRecord;
+DROP TABLE IF EXISTS FastestLap;
+DROP TABLE IF EXISTS Laps;
+DROP TABLE IF EXISTS PositionHistory;
+DROP TABLE IF EXISTS Position;
+DROP TABLE IF EXISTS Circuit;
+DROP TABLE IF EXISTS Session;
+DROP TABLE IF EXISTS Driver;
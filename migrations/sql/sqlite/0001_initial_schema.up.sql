@@ -0,0 +1,156 @@
+-- Esquema inicial: las tablas, índices y vista que server.go creaba con
+-- CREATE ... IF NOT EXISTS, de modo que aplicarla sobre un proxy.db existente no cambia nada.
+
+-- Tabla Driver
+CREATE TABLE IF NOT EXISTS Driver (
+	driver_number INTEGER PRIMARY KEY,
+	first_name TEXT NOT NULL,
+	last_name TEXT NOT NULL,
+	name_acronym TEXT NOT NULL,
+	team_name TEXT NOT NULL,
+	country_code TEXT NOT NULL
+);
+
+-- Tabla Session
+CREATE TABLE IF NOT EXISTS Session (
+	session_key INTEGER PRIMARY KEY,
+	session_name TEXT NOT NULL,
+	session_type TEXT NOT NULL,
+	location TEXT NOT NULL,
+	country_name TEXT NOT NULL,
+	year INTEGER NOT NULL,
+	circuit_short_name TEXT NOT NULL,
+	date_start TEXT NOT NULL
+);
+
+-- Tabla Circuit
+CREATE TABLE IF NOT EXISTS Circuit (
+	circuit_key INTEGER PRIMARY KEY,
+	circuit_short_name TEXT NOT NULL UNIQUE,
+	location TEXT NOT NULL,
+	country_name TEXT NOT NULL,
+	lap_length_km REAL
+);
+
+-- Tabla Position
+CREATE TABLE IF NOT EXISTS Position (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	position INTEGER NOT NULL,
+	date TEXT NOT NULL,
+	PRIMARY KEY (driver_number, session_key)
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+-- Tabla PositionHistory (todas las posiciones reportadas durante la carrera)
+CREATE TABLE IF NOT EXISTS PositionHistory (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	position INTEGER NOT NULL,
+	date TEXT NOT NULL,
+	PRIMARY KEY (session_key, driver_number, date),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+-- Tabla Laps
+CREATE TABLE IF NOT EXISTS Laps (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration REAL NOT NULL,
+	duration_sector_1 REAL NOT NULL,
+	duration_sector_2 REAL NOT NULL,
+	duration_sector_3 REAL NOT NULL,
+	st_speed REAL NOT NULL,
+	date_start TEXT NOT NULL,
+	PRIMARY KEY (driver_number, session_key, lap_number),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+-- Tabla FastestLap (vuelta rápida de cada carrera; en caso de empate hay una fila por vuelta empatada)
+CREATE TABLE IF NOT EXISTS FastestLap (
+	session_key INTEGER NOT NULL,
+	driver_number INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration REAL NOT NULL,
+	duration_sector_1 REAL,
+	duration_sector_2 REAL,
+	duration_sector_3 REAL,
+	date_start TEXT NOT NULL,
+	PRIMARY KEY (session_key, driver_number, lap_number),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+-- Tabla CircuitLapRecord (vuelta más rápida en carrera registrada en cada circuito)
+CREATE TABLE IF NOT EXISTS CircuitLapRecord (
+	circuit_key INTEGER PRIMARY KEY,
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration REAL NOT NULL,
+	FOREIGN KEY (circuit_key) REFERENCES Circuit(circuit_key),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+-- Tabla DriverSessionResult (estadísticas precalculadas de cada piloto en cada
+-- carrera; position es NULL si el piloto tiene vueltas pero no posición final)
+CREATE TABLE IF NOT EXISTS DriverSessionResult (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	year INTEGER NOT NULL,
+	position INTEGER,
+	best_lap_duration REAL,
+	max_speed REAL,
+	fastest_lap INTEGER NOT NULL,
+	laps_led INTEGER NOT NULL,
+	PRIMARY KEY (driver_number, session_key),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+-- Tabla DriverSeasonStats (totales precalculados de cada piloto por temporada)
+CREATE TABLE IF NOT EXISTS DriverSeasonStats (
+	driver_number INTEGER NOT NULL,
+	year INTEGER NOT NULL,
+	races INTEGER NOT NULL,
+	wins INTEGER NOT NULL,
+	podiums INTEGER NOT NULL,
+	fastest_laps INTEGER NOT NULL,
+	laps_led INTEGER NOT NULL,
+	max_speed REAL,
+	PRIMARY KEY (driver_number, year),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number)
+);
+
+-- Índices secundarios para las consultas de los handlers (las búsquedas por
+-- driver_number en Laps ya usan la clave primaria)
+CREATE INDEX IF NOT EXISTS idx_laps_session_duration ON Laps (session_key, lap_duration);
+CREATE INDEX IF NOT EXISTS idx_laps_session_speed ON Laps (session_key, st_speed);
+CREATE INDEX IF NOT EXISTS idx_position_session ON Position (session_key, position);
+CREATE INDEX IF NOT EXISTS idx_session_circuit ON Session (circuit_short_name, date_start);
+CREATE INDEX IF NOT EXISTS idx_session_year ON Session (year, date_start);
+CREATE INDEX IF NOT EXISTS idx_driver_season_stats_year ON DriverSeasonStats (year);
+
+-- Vista LapPosition: posición de cada piloto al terminar cada vuelta,
+-- tomando el último registro de PositionHistory antes del fin de la vuelta
+CREATE VIEW IF NOT EXISTS LapPosition AS
+SELECT
+	l.session_key,
+	l.driver_number,
+	l.lap_number,
+	l.lap_duration,
+	(
+		SELECT ph.position
+		FROM PositionHistory ph
+		WHERE ph.session_key = l.session_key
+		AND ph.driver_number = l.driver_number
+		AND julianday(ph.date) <= julianday(l.date_start) + l.lap_duration / 86400.0
+		ORDER BY ph.date DESC
+		LIMIT 1
+	) AS position
+FROM Laps l;
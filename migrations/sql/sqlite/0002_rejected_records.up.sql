@@ -0,0 +1,10 @@
+-- Tabla RejectedRecord (registros de OpenF1 descartados al ingerir por no pasar la
+-- validación). Cada ingesta reemplaza los rechazos de las solicitudes que vuelve a hacer.
+CREATE TABLE RejectedRecord (
+	source TEXT NOT NULL,
+	record_index INTEGER NOT NULL,
+	record TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	rejected_at TEXT NOT NULL,
+	PRIMARY KEY (source, record_index)
+);
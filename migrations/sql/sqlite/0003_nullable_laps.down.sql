@@ -0,0 +1,80 @@
+-- Vuelve a las columnas NOT NULL de Laps y FastestLap: los NULL se guardan otra vez como 0
+-- y las fechas faltantes como el comienzo de la carrera. lap_duration_estimated se pierde.
+
+DROP VIEW IF EXISTS LapPosition;
+
+CREATE TABLE Laps_old (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration REAL NOT NULL,
+	duration_sector_1 REAL NOT NULL,
+	duration_sector_2 REAL NOT NULL,
+	duration_sector_3 REAL NOT NULL,
+	st_speed REAL NOT NULL,
+	date_start TEXT NOT NULL,
+	PRIMARY KEY (driver_number, session_key, lap_number),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+INSERT INTO Laps_old
+SELECT
+	l.driver_number,
+	l.session_key,
+	l.lap_number,
+	COALESCE(l.lap_duration, 0),
+	COALESCE(l.duration_sector_1, 0),
+	COALESCE(l.duration_sector_2, 0),
+	COALESCE(l.duration_sector_3, 0),
+	COALESCE(l.st_speed, 0),
+	COALESCE(l.date_start, s.date_start, '')
+FROM Laps l
+LEFT JOIN Session s ON s.session_key = l.session_key;
+
+DROP TABLE Laps;
+ALTER TABLE Laps_old RENAME TO Laps;
+
+CREATE INDEX IF NOT EXISTS idx_laps_session_duration ON Laps (session_key, lap_duration);
+CREATE INDEX IF NOT EXISTS idx_laps_session_speed ON Laps (session_key, st_speed);
+
+CREATE TABLE FastestLap_old (
+	session_key INTEGER NOT NULL,
+	driver_number INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration REAL NOT NULL,
+	duration_sector_1 REAL,
+	duration_sector_2 REAL,
+	duration_sector_3 REAL,
+	date_start TEXT NOT NULL,
+	PRIMARY KEY (session_key, driver_number, lap_number),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+INSERT INTO FastestLap_old
+SELECT f.session_key, f.driver_number, f.lap_number, f.lap_duration,
+	f.duration_sector_1, f.duration_sector_2, f.duration_sector_3,
+	COALESCE(f.date_start, s.date_start, '')
+FROM FastestLap f
+LEFT JOIN Session s ON s.session_key = f.session_key;
+
+DROP TABLE FastestLap;
+ALTER TABLE FastestLap_old RENAME TO FastestLap;
+
+CREATE VIEW LapPosition AS
+SELECT
+	l.session_key,
+	l.driver_number,
+	l.lap_number,
+	l.lap_duration,
+	(
+		SELECT ph.position
+		FROM PositionHistory ph
+		WHERE ph.session_key = l.session_key
+		AND ph.driver_number = l.driver_number
+		AND julianday(ph.date) <= julianday(l.date_start) + l.lap_duration / 86400.0
+		ORDER BY ph.date DESC
+		LIMIT 1
+	) AS position
+FROM Laps l;
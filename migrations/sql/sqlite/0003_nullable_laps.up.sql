@@ -0,0 +1,110 @@
+-- Las columnas de Laps que OpenF1 puede mandar en null pasan a admitir NULL, en lugar de
+-- guardar 0 o datos inventados, y lap_duration_estimated marca las vueltas cuyo
+-- lap_duration se calculó sumando los tres sectores. FastestLap.date_start también admite
+-- NULL. SQLite no permite quitar un NOT NULL, así que ambas tablas se recrean (la vista
+-- LapPosition depende de Laps y se recrea al final).
+
+DROP VIEW IF EXISTS LapPosition;
+
+CREATE TABLE Laps_new (
+	driver_number INTEGER NOT NULL,
+	session_key INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration REAL,
+	lap_duration_estimated INTEGER NOT NULL DEFAULT 0,
+	duration_sector_1 REAL,
+	duration_sector_2 REAL,
+	duration_sector_3 REAL,
+	st_speed REAL,
+	date_start TEXT,
+	PRIMARY KEY (driver_number, session_key, lap_number),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+-- Los 0 que se guardaban por datos faltantes pasan a NULL. Un lap_duration igual a la suma
+-- de los sectores disponibles cuando falta alguno no es una vuelta completa y también pasa
+-- a NULL. Una fecha de inicio más de un día posterior al comienzo de la carrera es la hora
+-- de la ingesta, que se guardaba cuando faltaba date_start. Las vueltas ya guardadas con
+-- lap_duration estimado a partir de los tres sectores no se distinguen de las informadas.
+INSERT INTO Laps_new
+(driver_number, session_key, lap_number, lap_duration, lap_duration_estimated,
+ duration_sector_1, duration_sector_2, duration_sector_3, st_speed, date_start)
+SELECT
+	l.driver_number,
+	l.session_key,
+	l.lap_number,
+	CASE
+		WHEN l.lap_duration <= 0 THEN NULL
+		WHEN (l.duration_sector_1 <= 0 OR l.duration_sector_2 <= 0 OR l.duration_sector_3 <= 0)
+			AND ABS(l.lap_duration - (l.duration_sector_1 + l.duration_sector_2 + l.duration_sector_3)) <= 0.001
+			THEN NULL
+		ELSE l.lap_duration
+	END,
+	0,
+	NULLIF(l.duration_sector_1, 0),
+	NULLIF(l.duration_sector_2, 0),
+	NULLIF(l.duration_sector_3, 0),
+	NULLIF(l.st_speed, 0),
+	CASE WHEN julianday(l.date_start) > julianday(s.date_start) + 1 THEN NULL ELSE l.date_start END
+FROM Laps l
+LEFT JOIN Session s ON s.session_key = l.session_key;
+
+DROP TABLE Laps;
+ALTER TABLE Laps_new RENAME TO Laps;
+
+CREATE INDEX IF NOT EXISTS idx_laps_session_duration ON Laps (session_key, lap_duration);
+CREATE INDEX IF NOT EXISTS idx_laps_session_speed ON Laps (session_key, st_speed);
+
+CREATE TABLE FastestLap_new (
+	session_key INTEGER NOT NULL,
+	driver_number INTEGER NOT NULL,
+	lap_number INTEGER NOT NULL,
+	lap_duration REAL NOT NULL,
+	duration_sector_1 REAL,
+	duration_sector_2 REAL,
+	duration_sector_3 REAL,
+	date_start TEXT,
+	PRIMARY KEY (session_key, driver_number, lap_number),
+	FOREIGN KEY (driver_number) REFERENCES Driver(driver_number),
+	FOREIGN KEY (session_key) REFERENCES Session(session_key)
+);
+
+INSERT INTO FastestLap_new
+SELECT session_key, driver_number, lap_number, lap_duration,
+	duration_sector_1, duration_sector_2, duration_sector_3, date_start
+FROM FastestLap;
+
+DROP TABLE FastestLap;
+ALTER TABLE FastestLap_new RENAME TO FastestLap;
+
+-- Vista LapPosition: posición de cada piloto al terminar cada vuelta, tomando el último
+-- registro de PositionHistory antes del fin de la vuelta. Si falta date_start o
+-- lap_duration, el fin de la vuelta es el comienzo de la siguiente vuelta del piloto; si
+-- tampoco se conoce, position queda NULL.
+CREATE VIEW LapPosition AS
+SELECT
+	l.session_key,
+	l.driver_number,
+	l.lap_number,
+	l.lap_duration,
+	l.lap_duration_estimated,
+	(
+		SELECT ph.position
+		FROM PositionHistory ph
+		WHERE ph.session_key = l.session_key
+		AND ph.driver_number = l.driver_number
+		AND julianday(ph.date) <= COALESCE(
+			julianday(l.date_start) + l.lap_duration / 86400.0,
+			(
+				SELECT julianday(n.date_start)
+				FROM Laps n
+				WHERE n.session_key = l.session_key
+				AND n.driver_number = l.driver_number
+				AND n.lap_number = l.lap_number + 1
+			)
+		)
+		ORDER BY ph.date DESC
+		LIMIT 1
+	) AS position
+FROM Laps l;
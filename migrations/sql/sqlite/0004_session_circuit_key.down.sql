@@ -0,0 +1,22 @@
+-- Vuelve a unir las carreras con Circuit por circuit_short_name. Si hay circuitos con el
+-- mismo nombre se conserva el de menor circuit_key.
+
+DROP INDEX IF EXISTS idx_session_circuit_key;
+ALTER TABLE Session DROP COLUMN circuit_key;
+CREATE INDEX IF NOT EXISTS idx_session_circuit ON Session (circuit_short_name, date_start);
+
+CREATE TABLE Circuit_old (
+	circuit_key INTEGER PRIMARY KEY,
+	circuit_short_name TEXT NOT NULL UNIQUE,
+	location TEXT NOT NULL,
+	country_name TEXT NOT NULL,
+	lap_length_km REAL
+);
+
+INSERT OR IGNORE INTO Circuit_old
+SELECT circuit_key, circuit_short_name, location, country_name, lap_length_km
+FROM Circuit
+ORDER BY circuit_key;
+
+DROP TABLE Circuit;
+ALTER TABLE Circuit_old RENAME TO Circuit;
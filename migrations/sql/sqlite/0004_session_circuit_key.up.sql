@@ -0,0 +1,33 @@
+-- Session guarda el circuit_key de OpenF1 y las carreras se unen con Circuit por esa
+-- columna en lugar de por circuit_short_name, que puede cambiar entre temporadas o
+-- repetirse entre circuitos. Por lo mismo circuit_short_name deja de ser UNIQUE en
+-- Circuit: con la restricción, el segundo circuito con el mismo nombre no se guardaba.
+-- SQLite no permite quitar un UNIQUE, así que Circuit se recrea.
+
+CREATE TABLE Circuit_new (
+	circuit_key INTEGER PRIMARY KEY,
+	circuit_short_name TEXT NOT NULL,
+	location TEXT NOT NULL,
+	country_name TEXT NOT NULL,
+	lap_length_km REAL
+);
+
+INSERT INTO Circuit_new
+SELECT circuit_key, circuit_short_name, location, country_name, lap_length_km
+FROM Circuit;
+
+DROP TABLE Circuit;
+ALTER TABLE Circuit_new RENAME TO Circuit;
+
+-- Las carreras ya guardadas toman el circuit_key del circuito con su nombre; si no hay
+-- ninguno queda NULL hasta que la próxima ingesta lo complete
+ALTER TABLE Session ADD COLUMN circuit_key INTEGER;
+
+UPDATE Session SET circuit_key = (
+	SELECT c.circuit_key
+	FROM Circuit c
+	WHERE c.circuit_short_name = Session.circuit_short_name
+);
+
+DROP INDEX IF EXISTS idx_session_circuit;
+CREATE INDEX idx_session_circuit_key ON Session (circuit_key, date_start);
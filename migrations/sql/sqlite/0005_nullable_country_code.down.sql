@@ -0,0 +1,17 @@
+-- Vuelve a la columna country_code NOT NULL: los pilotos sin país se guardan con ''.
+
+CREATE TABLE Driver_old (
+	driver_number INTEGER PRIMARY KEY,
+	first_name TEXT NOT NULL,
+	last_name TEXT NOT NULL,
+	name_acronym TEXT NOT NULL,
+	team_name TEXT NOT NULL,
+	country_code TEXT NOT NULL
+);
+
+INSERT INTO Driver_old
+SELECT driver_number, first_name, last_name, name_acronym, team_name, COALESCE(country_code, '')
+FROM Driver;
+
+DROP TABLE Driver;
+ALTER TABLE Driver_old RENAME TO Driver;
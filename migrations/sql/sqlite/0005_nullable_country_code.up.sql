@@ -0,0 +1,19 @@
+-- Driver.country_code admite NULL: OpenF1 no informa el país de algunos pilotos (reservas,
+-- pilotos de prácticas) y esos registros se descartaban enteros. SQLite no permite quitar
+-- un NOT NULL, así que Driver se recrea.
+
+CREATE TABLE Driver_new (
+	driver_number INTEGER PRIMARY KEY,
+	first_name TEXT NOT NULL,
+	last_name TEXT NOT NULL,
+	name_acronym TEXT NOT NULL,
+	team_name TEXT NOT NULL,
+	country_code TEXT
+);
+
+INSERT INTO Driver_new
+SELECT driver_number, first_name, last_name, name_acronym, team_name, country_code
+FROM Driver;
+
+DROP TABLE Driver;
+ALTER TABLE Driver_new RENAME TO Driver;
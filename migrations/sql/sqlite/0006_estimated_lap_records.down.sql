@@ -0,0 +1,2 @@
+-- Los registros borrados no se restauran: la próxima ingesta recalcula FastestLap y los
+-- récords con el criterio del código que esté corriendo.
@@ -0,0 +1,28 @@
+-- Position guardaba la primera posición que OpenF1 reportó de cada piloto en la carrera
+-- (la ingesta usaba ON CONFLICT DO NOTHING) en vez de la final. Se reemplaza por la última
+-- de PositionHistory; las estadísticas precalculadas se recalculan en la próxima ingesta
+-- o con rebuild-stats.
+
+UPDATE Position
+SET
+	position = (
+		SELECT ph.position
+		FROM PositionHistory ph
+		WHERE ph.driver_number = Position.driver_number
+		AND ph.session_key = Position.session_key
+		ORDER BY ph.date DESC
+		LIMIT 1
+	),
+	date = (
+		SELECT MAX(ph.date)
+		FROM PositionHistory ph
+		WHERE ph.driver_number = Position.driver_number
+		AND ph.session_key = Position.session_key
+	)
+WHERE EXISTS (
+	SELECT 1
+	FROM PositionHistory ph
+	WHERE ph.driver_number = Position.driver_number
+	AND ph.session_key = Position.session_key
+	AND ph.date > Position.date
+);
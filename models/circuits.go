@@ -0,0 +1,51 @@
+package models
+
+// Circuit es un elemento de GET /api/circuito. LapLengthKm es nil si el largo no es conocido.
+type Circuit struct {
+	CircuitKey       int      `json:"circuit_key"`
+	CircuitShortName string   `json:"circuit_short_name"`
+	Location         string   `json:"location"`
+	CountryName      string   `json:"country_name"`
+	LapLengthKm      *float64 `json:"lap_length_km"`
+	Races            int      `json:"races"`
+}
+
+// CircuitDetail es la respuesta de GET /api/circuito/:key
+type CircuitDetail struct {
+	CircuitKey       int               `json:"circuit_key"`
+	CircuitShortName string            `json:"circuit_short_name"`
+	Location         string            `json:"location"`
+	CountryName      string            `json:"country_name"`
+	LapLengthKm      *float64          `json:"lap_length_km"`
+	Races            []CircuitRace     `json:"races"`
+	PastWinners      []CircuitWinner   `json:"past_winners"`
+	LapRecord        *CircuitLapRecord `json:"lap_record"`
+}
+
+// CircuitRace es una carrera disputada en el circuito. Las velocidades promedio
+// son nil si no se conoce el largo de vuelta.
+type CircuitRace struct {
+	SessionKey      int      `json:"session_key"`
+	Year            int      `json:"year"`
+	DateStart       string   `json:"date_start"`
+	Winner          string   `json:"winner"`
+	Team            string   `json:"team"`
+	BestLapDuration *float64 `json:"best_lap_duration"`
+	AverageSpeedKMH *float64 `json:"average_speed_kmh"`
+}
+
+// CircuitWinner es el ganador de una edición de la carrera en el circuito
+type CircuitWinner struct {
+	Year   int    `json:"year"`
+	Driver string `json:"driver"`
+	Team   string `json:"team"`
+}
+
+// CircuitLapRecord es el récord de vuelta del circuito entre todas las temporadas ingeridas
+type CircuitLapRecord struct {
+	Driver          string   `json:"driver"`
+	LapDuration     float64  `json:"lap_duration"`
+	SessionKey      int      `json:"session_key"`
+	Year            int      `json:"year"`
+	AverageSpeedKMH *float64 `json:"average_speed_kmh"`
+}
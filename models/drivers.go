@@ -0,0 +1,41 @@
+package models
+
+// Driver es un elemento de GET /api/corredor. CountryCode es nil si OpenF1 no informó
+// el país del piloto.
+type Driver struct {
+	FirstName    string  `json:"first_name"`
+	LastName     string  `json:"last_name"`
+	DriverNumber int     `json:"driver_number"`
+	TeamName     string  `json:"team_name"`
+	CountryCode  *string `json:"country_code"`
+}
+
+// DriverDetail es la respuesta de GET /api/corredor/detalle/:id
+type DriverDetail struct {
+	DriverID           string             `json:"driver_id"`
+	PerformanceSummary PerformanceSummary `json:"performance_summary"`
+	RaceResults        []DriverRaceResult `json:"race_results"`
+}
+
+// PerformanceSummary resume el desempeño de un piloto en todas las carreras ingeridas.
+// MaxSpeed es nil si ninguna de sus vueltas tiene velocidad informada.
+type PerformanceSummary struct {
+	Wins         int      `json:"wins"`
+	Top3Finishes int      `json:"top_3_finishes"`
+	MaxSpeed     *float64 `json:"max_speed"`
+	LapsLed      int      `json:"laps_led"`
+}
+
+// DriverRaceResult es el resultado de un piloto en una carrera. MaxSpeed y
+// BestLapDuration son nil si OpenF1 no informó la velocidad o el tiempo de ninguna vuelta.
+type DriverRaceResult struct {
+	SessionKey       int      `json:"session_key"`
+	CircuitShortName string   `json:"circuit_short_name"`
+	CountryName      string   `json:"country_name"`
+	Race             string   `json:"race"`
+	Position         int      `json:"position"`
+	FastestLap       bool     `json:"fastest_lap"`
+	MaxSpeed         *float64 `json:"max_speed"`
+	BestLapDuration  *float64 `json:"best_lap_duration"`
+	LapsLed          int      `json:"laps_led"`
+}
@@ -0,0 +1,53 @@
+// Package models contiene los tipos de request/response de la API de estadísticas,
+// compartidos por los handlers de api y por cliente.go para que el contrato
+// entre ambos lo verifique el compilador.
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ErrorResponse es el cuerpo de todas las respuestas de error de la API
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describe un error: un código estable para programas y un mensaje para personas
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ResultPosition es la posición de un resultado. En JSON se serializa como número
+// (1, 2, 3...) o, si tiene etiqueta, como texto (por ejemplo "Último").
+type ResultPosition struct {
+	Number int
+	Label  string
+}
+
+func (p ResultPosition) MarshalJSON() ([]byte, error) {
+	if p.Label != "" {
+		return json.Marshal(p.Label)
+	}
+	return json.Marshal(p.Number)
+}
+
+func (p *ResultPosition) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &p.Number); err == nil {
+		p.Label = ""
+		return nil
+	}
+	if err := json.Unmarshal(data, &p.Label); err != nil {
+		return fmt.Errorf("posición inválida: %s", data)
+	}
+	p.Number = 0
+	return nil
+}
+
+func (p ResultPosition) String() string {
+	if p.Label != "" {
+		return p.Label
+	}
+	return fmt.Sprintf("%d", p.Number)
+}
@@ -0,0 +1,98 @@
+package models
+
+// Race es un elemento de GET /api/carrera
+type Race struct {
+	SessionKey       int    `json:"session_key"`
+	CountryName      string `json:"country_name"`
+	DateStart        string `json:"date_start"`
+	Year             int    `json:"year"`
+	CircuitShortName string `json:"circuit_short_name"`
+}
+
+// RaceDetail es la respuesta de GET /api/carrera/detalle/:id
+type RaceDetail struct {
+	RaceID           string          `json:"race_id"`
+	CountryName      string          `json:"country_name"`
+	DateStart        string          `json:"date_start"`
+	Year             int             `json:"year"`
+	CircuitShortName string          `json:"circuit_short_name"`
+	Results          []RaceResult    `json:"results"`
+	FastestLap       RaceFastestLap  `json:"fastest_lap"`
+	MaxSpeed         RaceMaxSpeed    `json:"max_speed"`
+	LapsLed          []DriverLapsLed `json:"laps_led"`
+	LeadChanges      int             `json:"lead_changes"`
+	CircuitRecord    *CircuitRecord  `json:"circuit_record"`
+	NewCircuitRecord bool            `json:"new_circuit_record"`
+}
+
+// RaceResult es una fila de resultados de la carrera (podio y último lugar). Country es
+// nil si no se conoce el país del piloto.
+type RaceResult struct {
+	Position ResultPosition `json:"position"`
+	Driver   string         `json:"driver"`
+	Team     string         `json:"team"`
+	Country  *string        `json:"country"`
+}
+
+// RaceFastestLap es la vuelta rápida de la carrera. Los sectores son nil cuando
+// OpenF1 no los informó; TiedDrivers lista a otros pilotos que marcaron el mismo tiempo.
+type RaceFastestLap struct {
+	Driver      string   `json:"driver"`
+	TotalTime   float64  `json:"total_time"`
+	Sector1     *float64 `json:"sector_1"`
+	Sector2     *float64 `json:"sector_2"`
+	Sector3     *float64 `json:"sector_3"`
+	TiedDrivers []string `json:"tied_drivers"`
+}
+
+// RaceMaxSpeed es la mayor velocidad en la trampa de velocidad durante la carrera
+type RaceMaxSpeed struct {
+	Driver   string  `json:"driver"`
+	SpeedKMH float64 `json:"speed_kmh"`
+}
+
+// DriverLapsLed es la cantidad de vueltas que un piloto lideró en una carrera
+type DriverLapsLed struct {
+	Driver       string `json:"driver"`
+	DriverNumber int    `json:"driver_number"`
+	Laps         int    `json:"laps"`
+}
+
+// CircuitRecord es el récord de vuelta vigente del circuito de una carrera
+type CircuitRecord struct {
+	Driver      string  `json:"driver"`
+	LapDuration float64 `json:"lap_duration"`
+	SessionKey  int     `json:"session_key"`
+	Year        int     `json:"year"`
+}
+
+// LapChart es la respuesta de GET /api/carrera/detalle/:id/vueltas
+type LapChart struct {
+	RaceID string        `json:"race_id"`
+	Laps   []LapChartLap `json:"laps"`
+}
+
+// LapChartLap son las posiciones de todos los pilotos al terminar una vuelta
+type LapChartLap struct {
+	LapNumber int                `json:"lap_number"`
+	Leader    *LapLeader         `json:"leader"`
+	Positions []LapChartPosition `json:"positions"`
+}
+
+// LapLeader es el piloto que iba primero al terminar una vuelta
+type LapLeader struct {
+	DriverNumber int    `json:"driver_number"`
+	Driver       string `json:"driver"`
+}
+
+// LapChartPosition es la posición y el tiempo de un piloto en una vuelta.
+// Position es nil si no hay registros de posición hasta el final de la vuelta y
+// LapDuration es nil si OpenF1 no informó el tiempo ni los tres sectores de la vuelta.
+// LapDurationEstimated indica que LapDuration es la suma de los sectores.
+type LapChartPosition struct {
+	Position             *int     `json:"position"`
+	DriverNumber         int      `json:"driver_number"`
+	Driver               string   `json:"driver"`
+	LapDuration          *float64 `json:"lap_duration"`
+	LapDurationEstimated bool     `json:"lap_duration_estimated"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// SeasonSummary es la respuesta de GET /api/temporada/resumen
+type SeasonSummary struct {
+	Season          int                 `json:"season"`
+	Top3Winners     []SeasonWinner      `json:"top_3_winners"`
+	Top3FastestLaps []SeasonFastestLaps `json:"top_3_fastest_laps"`
+	// Se mantiene la clave top_3_pole_positions por compatibilidad, pero su contenido
+	// es el top 3 de pilotos con más podios
+	Top3Podiums []SeasonPodiums `json:"top_3_pole_positions"`
+	Top3LapsLed []SeasonLapsLed `json:"top_3_laps_led"`
+}
+
+// RankedDriver son los campos comunes de cada fila de los rankings de temporada. Country
+// es nil si no se conoce el país del piloto.
+type RankedDriver struct {
+	Position int     `json:"position"`
+	Driver   string  `json:"driver"`
+	Team     string  `json:"team"`
+	Country  *string `json:"country"`
+}
+
+// SeasonWinner es una fila del top de victorias
+type SeasonWinner struct {
+	RankedDriver
+	Wins int `json:"wins"`
+}
+
+// SeasonFastestLaps es una fila del top de vueltas rápidas
+type SeasonFastestLaps struct {
+	RankedDriver
+	FastestLaps int `json:"fastest_laps"`
+}
+
+// SeasonPodiums es una fila del top de podios
+type SeasonPodiums struct {
+	RankedDriver
+	Podiums int `json:"podiums"`
+}
+
+// SeasonLapsLed es una fila del top de vueltas lideradas
+type SeasonLapsLed struct {
+	RankedDriver
+	LapsLed int `json:"laps_led"`
+}
@@ -0,0 +1,122 @@
+package v2
+
+import "f1_statshub_system/models"
+
+// NewDriverDetail convierte el detalle de un piloto de v1 a v2
+func NewDriverDetail(driverNumber int, d models.DriverDetail) DriverDetail {
+	detail := DriverDetail{
+		DriverNumber: driverNumber,
+		Summary: DriverSummary{
+			Wins:        d.PerformanceSummary.Wins,
+			Podiums:     d.PerformanceSummary.Top3Finishes,
+			MaxSpeedKMH: d.PerformanceSummary.MaxSpeed,
+			LapsLed:     d.PerformanceSummary.LapsLed,
+		},
+		Results: make([]DriverSessionResult, 0, len(d.RaceResults)),
+	}
+	for _, r := range d.RaceResults {
+		detail.Results = append(detail.Results, DriverSessionResult{
+			SessionKey:       r.SessionKey,
+			CountryName:      r.CountryName,
+			CircuitShortName: r.CircuitShortName,
+			Position:         r.Position,
+			FastestLap:       r.FastestLap,
+			MaxSpeedKMH:      r.MaxSpeed,
+			BestLapDuration:  r.BestLapDuration,
+			LapsLed:          r.LapsLed,
+		})
+	}
+	return detail
+}
+
+// NewSessionDetail convierte el detalle de una carrera a v2. d.Results es el podio y
+// lastPlace el último lugar, que queda nil si no tiene piloto.
+func NewSessionDetail(sessionKey int, d models.RaceDetail, lastPlace models.RaceResult) SessionDetail {
+	detail := SessionDetail{
+		SessionKey:       sessionKey,
+		CountryName:      d.CountryName,
+		DateStart:        d.DateStart,
+		Year:             d.Year,
+		CircuitShortName: d.CircuitShortName,
+		Podium:           []SessionResult{},
+		LapsLed:          d.LapsLed,
+		LeadChanges:      d.LeadChanges,
+		CircuitRecord:    d.CircuitRecord,
+		NewCircuitRecord: d.NewCircuitRecord,
+	}
+	for _, r := range d.Results {
+		detail.Podium = append(detail.Podium, newSessionResult(r))
+	}
+	if lastPlace.Driver != "" {
+		result := newSessionResult(lastPlace)
+		detail.LastPlace = &result
+	}
+	if d.FastestLap.Driver != "" {
+		detail.FastestLap = &FastestLap{
+			Driver:      d.FastestLap.Driver,
+			LapDuration: d.FastestLap.TotalTime,
+			Sector1:     d.FastestLap.Sector1,
+			Sector2:     d.FastestLap.Sector2,
+			Sector3:     d.FastestLap.Sector3,
+			TiedDrivers: d.FastestLap.TiedDrivers,
+		}
+	}
+	if d.MaxSpeed.Driver != "" {
+		detail.MaxSpeed = &MaxSpeed{
+			Driver:   d.MaxSpeed.Driver,
+			SpeedKMH: d.MaxSpeed.SpeedKMH,
+		}
+	}
+	return detail
+}
+
+func newSessionResult(r models.RaceResult) SessionResult {
+	return SessionResult{
+		Position: r.Position.Number,
+		Driver:   r.Driver,
+		Team:     r.Team,
+		Country:  r.Country,
+	}
+}
+
+// NewLapChart convierte las posiciones vuelta a vuelta de v1 a v2
+func NewLapChart(sessionKey int, c models.LapChart) LapChart {
+	chart := LapChart{
+		SessionKey: sessionKey,
+		Laps:       make([]LapChartLap, 0, len(c.Laps)),
+	}
+	for _, l := range c.Laps {
+		lap := LapChartLap{
+			LapNumber: l.LapNumber,
+			Positions: make([]LapChartPosition, 0, len(l.Positions)),
+		}
+		if l.Leader != nil {
+			lap.Leader = &LapLeader{
+				DriverNumber: l.Leader.DriverNumber,
+				Driver:       l.Leader.Driver,
+			}
+		}
+		for _, p := range l.Positions {
+			lap.Positions = append(lap.Positions, LapChartPosition{
+				Position:             p.Position,
+				DriverNumber:         p.DriverNumber,
+				Driver:               p.Driver,
+				LapDuration:          p.LapDuration,
+				LapDurationEstimated: p.LapDurationEstimated,
+			})
+		}
+		chart.Laps = append(chart.Laps, lap)
+	}
+	return chart
+}
+
+// NewSeasonSummary convierte el resumen de temporada de v1 a v2
+func NewSeasonSummary(s models.SeasonSummary) SeasonSummary {
+	return SeasonSummary{
+		Season:          s.Season,
+		Top3Winners:     s.Top3Winners,
+		Top3FastestLaps: s.Top3FastestLaps,
+		Top3Podiums:     s.Top3Podiums,
+		Top3LapsLed:     s.Top3LapsLed,
+	}
+}
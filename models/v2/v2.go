@@ -0,0 +1,134 @@
+// Package v2 contiene los tipos de respuesta de /api/v2, con nombres de recursos y
+// campos en inglés y sin textos generados (por ejemplo "GP de ..." o "Último").
+// Se construyen a partir de los tipos de models que arman los handlers; los tipos
+// que ya eran consistentes se reutilizan como alias y deben pasar a ser propios si
+// su versión de v1 cambia.
+package v2
+
+import "f1_statshub_system/models"
+
+type (
+	Driver        = models.Driver
+	Session       = models.Race
+	DriverLapsLed = models.DriverLapsLed
+	CircuitRecord = models.CircuitRecord
+	SeasonWinner  = models.SeasonWinner
+	SeasonFastest = models.SeasonFastestLaps
+	SeasonPodiums = models.SeasonPodiums
+	SeasonLapsLed = models.SeasonLapsLed
+	Circuit       = models.Circuit
+	CircuitDetail = models.CircuitDetail
+)
+
+// DriverDetail es la respuesta de GET /api/v2/drivers/:number
+type DriverDetail struct {
+	DriverNumber int                   `json:"driver_number"`
+	Summary      DriverSummary         `json:"summary"`
+	Results      []DriverSessionResult `json:"results"`
+}
+
+// DriverSummary resume el desempeño de un piloto en todas las carreras ingeridas.
+// MaxSpeedKMH es nil si ninguna de sus vueltas tiene velocidad informada.
+type DriverSummary struct {
+	Wins        int      `json:"wins"`
+	Podiums     int      `json:"podiums"`
+	MaxSpeedKMH *float64 `json:"max_speed_kmh"`
+	LapsLed     int      `json:"laps_led"`
+}
+
+// DriverSessionResult es el resultado de un piloto en una carrera. MaxSpeedKMH y
+// BestLapDuration son nil si OpenF1 no informó la velocidad o el tiempo de ninguna vuelta.
+type DriverSessionResult struct {
+	SessionKey       int      `json:"session_key"`
+	CountryName      string   `json:"country_name"`
+	CircuitShortName string   `json:"circuit_short_name"`
+	Position         int      `json:"position"`
+	FastestLap       bool     `json:"fastest_lap"`
+	MaxSpeedKMH      *float64 `json:"max_speed_kmh"`
+	BestLapDuration  *float64 `json:"best_lap_duration"`
+	LapsLed          int      `json:"laps_led"`
+}
+
+// SessionDetail es la respuesta de GET /api/v2/sessions/:key. LastPlace, FastestLap
+// y MaxSpeed son nil si la carrera no tiene datos para calcularlos.
+type SessionDetail struct {
+	SessionKey       int             `json:"session_key"`
+	CountryName      string          `json:"country_name"`
+	DateStart        string          `json:"date_start"`
+	Year             int             `json:"year"`
+	CircuitShortName string          `json:"circuit_short_name"`
+	Podium           []SessionResult `json:"podium"`
+	LastPlace        *SessionResult  `json:"last_place"`
+	FastestLap       *FastestLap     `json:"fastest_lap"`
+	MaxSpeed         *MaxSpeed       `json:"max_speed"`
+	LapsLed          []DriverLapsLed `json:"laps_led"`
+	LeadChanges      int             `json:"lead_changes"`
+	CircuitRecord    *CircuitRecord  `json:"circuit_record"`
+	NewCircuitRecord bool            `json:"new_circuit_record"`
+}
+
+// SessionResult es la posición final de un piloto en una carrera; Country es nil si no
+// se conoce el país del piloto
+type SessionResult struct {
+	Position int     `json:"position"`
+	Driver   string  `json:"driver"`
+	Team     string  `json:"team"`
+	Country  *string `json:"country"`
+}
+
+// FastestLap es la vuelta rápida de una carrera; los sectores son nil si OpenF1 no los informó
+type FastestLap struct {
+	Driver      string   `json:"driver"`
+	LapDuration float64  `json:"lap_duration"`
+	Sector1     *float64 `json:"sector_1"`
+	Sector2     *float64 `json:"sector_2"`
+	Sector3     *float64 `json:"sector_3"`
+	TiedDrivers []string `json:"tied_drivers"`
+}
+
+// MaxSpeed es la mayor velocidad en la trampa de velocidad durante una carrera
+type MaxSpeed struct {
+	Driver   string  `json:"driver"`
+	SpeedKMH float64 `json:"speed_kmh"`
+}
+
+// LapChart es la respuesta de GET /api/v2/sessions/:key/laps
+type LapChart struct {
+	SessionKey int           `json:"session_key"`
+	Laps       []LapChartLap `json:"laps"`
+}
+
+// LapChartLap son las posiciones de todos los pilotos al terminar una vuelta; Leader es
+// nil si nadie tiene la posición 1 en esa vuelta
+type LapChartLap struct {
+	LapNumber int                `json:"lap_number"`
+	Leader    *LapLeader         `json:"leader"`
+	Positions []LapChartPosition `json:"positions"`
+}
+
+// LapLeader es el piloto que iba primero al terminar una vuelta
+type LapLeader struct {
+	DriverNumber int    `json:"driver_number"`
+	Driver       string `json:"driver"`
+}
+
+// LapChartPosition es la posición y el tiempo de un piloto en una vuelta. Position es
+// nil si no hay registros de posición hasta el final de la vuelta y LapDuration si OpenF1
+// no informó el tiempo ni los tres sectores; LapDurationEstimated indica que LapDuration
+// es la suma de los sectores.
+type LapChartPosition struct {
+	Position             *int     `json:"position"`
+	DriverNumber         int      `json:"driver_number"`
+	Driver               string   `json:"driver"`
+	LapDuration          *float64 `json:"lap_duration"`
+	LapDurationEstimated bool     `json:"lap_duration_estimated"`
+}
+
+// SeasonSummary es la respuesta de GET /api/v2/seasons/:year
+type SeasonSummary struct {
+	Season          int             `json:"season"`
+	Top3Winners     []SeasonWinner  `json:"top_3_winners"`
+	Top3FastestLaps []SeasonFastest `json:"top_3_fastest_laps"`
+	Top3Podiums     []SeasonPodiums `json:"top_3_podiums"`
+	Top3LapsLed     []SeasonLapsLed `json:"top_3_laps_led"`
+}
@@ -0,0 +1,12 @@
+// Package openapi contiene la especificación OpenAPI 3 de la API de estadísticas,
+// embebida en el binario para que api la sirva en /api/openapi.json.
+package openapi
+
+import _ "embed"
+
+// Spec es el documento OpenAPI en JSON. Al cambiar un handler o un tipo de models
+// hay que actualizar openapi.json para que los clientes generados no queden desfasados;
+// los tests de api validan las respuestas de cada ruta contra este documento.
+//
+//go:embed openapi.json
+var Spec []byte
@@ -0,0 +1,93 @@
+package openf1
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// clock da la hora y hace las esperas del limiter y de los reintentos de Client; los
+// tests lo reemplazan por uno que avanza sin esperar
+type clock interface {
+	Now() time.Time
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// realClock es el reloj del sistema
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error { return sleep(ctx, d) }
+
+// limiter reparte las solicitudes en turnos separados por interval. Cada llamada a
+// Wait reserva el próximo turno libre y espera hasta que llegue. Pause suspende todos
+// los turnos, incluso los ya reservados, hasta resume.
+type limiter struct {
+	mu       sync.Mutex
+	clock    clock
+	interval time.Duration
+	next     time.Time
+	resume   time.Time
+}
+
+// newLimiter crea un limiter de requestsPerSecond solicitudes por segundo (sin límite si es 0)
+func newLimiter(requestsPerSecond float64) *limiter {
+	l := &limiter{clock: realClock{}}
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return l
+}
+
+// Wait bloquea hasta el próximo turno libre o hasta que se cancele ctx
+func (l *limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := l.clock.Now()
+		if l.next.Before(now) {
+			l.next = now
+		}
+		wait := l.next.Sub(now)
+		l.next = l.next.Add(l.interval)
+		l.mu.Unlock()
+
+		if err := l.clock.Sleep(ctx, wait); err != nil {
+			return err
+		}
+
+		// Si mientras esperaba se pausó el limiter, el turno ya no vale: pedir otro
+		l.mu.Lock()
+		paused := l.clock.Now().Before(l.resume)
+		l.mu.Unlock()
+		if !paused {
+			return nil
+		}
+	}
+}
+
+// Pause posterga todos los turnos hasta dentro de d (por ejemplo, ante un 429)
+func (l *limiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if resume := l.clock.Now().Add(d); l.resume.Before(resume) {
+		l.resume = resume
+	}
+	if l.next.Before(l.resume) {
+		l.next = l.resume
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,72 @@
+package openf1
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterWait(t *testing.T) {
+	l := newLimiter(20)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// El primer turno es inmediato y los otros cuatro van cada 50ms
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("5 turnos a 20 por segundo tardaron %v, se esperaban al menos 200ms", elapsed)
+	}
+}
+
+func TestLimiterWaitCanceled(t *testing.T) {
+	l := newLimiter(0)
+	l.Pause(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait con el limiter pausado = %v, se esperaba %v", err, context.DeadlineExceeded)
+	}
+}
+
+// TestLimiterPause comprueba que Pause demore a todos los workers, también a los que ya
+// tenían un turno reservado cuando llegó la pausa
+func TestLimiterPause(t *testing.T) {
+	const pause = 150 * time.Millisecond
+	l := newLimiter(10)
+
+	// El primer turno es inmediato; los workers reservan los siguientes (cada 100ms)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+
+	const workers = 4
+	var wg sync.WaitGroup
+	waited := make(chan time.Duration, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background()); err != nil {
+				t.Error(err)
+			}
+			waited <- time.Since(start)
+		}()
+	}
+
+	// Los workers ya están esperando sus turnos cuando llega la pausa (por ejemplo un 429)
+	time.Sleep(20 * time.Millisecond)
+	l.Pause(pause)
+	wg.Wait()
+	close(waited)
+
+	for d := range waited {
+		if d < 20*time.Millisecond+pause {
+			t.Errorf("un worker siguió %v después de empezar, antes de que terminara la pausa", d)
+		}
+	}
+}
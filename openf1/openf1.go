@@ -0,0 +1,245 @@
+// Package openf1 es el cliente de la API de OpenF1 que usa la ingesta. Un mismo Client
+// se comparte entre todas las goroutines que descargan datos: limita la cantidad de
+// solicitudes por segundo entre todas ellas y, si OpenF1 responde 429, frena a todas
+// durante el tiempo que indique Retry-After antes de reintentar.
+//
+// Cada solicitud tiene un timeout. Las fallas transitorias (errores de red, 429 y 5xx) se
+// reintentan con backoff exponencial con jitter; el resto se devuelve enseguida. Los errores
+// son de tipo *StatusError, *DecodeError o *RequestError, envueltos en *RetryError si se
+// agotaron los intentos.
+package openf1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout es el tiempo máximo de cada solicitud si no se configura otro HTTPClient
+const DefaultTimeout = 30 * time.Second
+
+const (
+	// BaseBackoff es la espera antes del segundo intento; se duplica en cada intento
+	BaseBackoff = 500 * time.Millisecond
+	// MaxBackoff es la espera máxima entre intentos
+	MaxBackoff = 30 * time.Second
+)
+
+// maxErrorBody es cuánto del cuerpo de una respuesta de error se guarda en StatusError
+const maxErrorBody = 200
+
+// Client descarga registros de OpenF1 respetando el límite de solicitudes por segundo.
+// BaseURL, HTTPClient y MaxRetries se pueden modificar después de crearlo con New.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// MaxRetries es la cantidad máxima de intentos por solicitud ante fallas transitorias
+	MaxRetries int
+
+	limiter *limiter
+}
+
+// New crea un cliente para la API en baseURL que hace como máximo requestsPerSecond
+// solicitudes por segundo (sin límite si es 0), con un timeout de DefaultTimeout por solicitud
+func New(baseURL string, requestsPerSecond float64, maxRetries int) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+		MaxRetries: maxRetries,
+		limiter:    newLimiter(requestsPerSecond),
+	}
+}
+
+// StatusError es una respuesta de OpenF1 con un código distinto de 200
+type StatusError struct {
+	StatusCode int
+	URL        string
+	// Body es el comienzo del cuerpo de la respuesta, para diagnóstico
+	Body string
+	// RetryAfter es la espera pedida por OpenF1 en el header Retry-After (0 si no vino)
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("OpenF1 respondió %d para %s: %s", e.StatusCode, e.URL, e.Body)
+}
+
+// DecodeError es una respuesta 200 de OpenF1 que no es el JSON esperado
+type DecodeError struct {
+	URL         string
+	ContentType string
+	Err         error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("respuesta inválida de OpenF1 para %s (Content-Type %q): %v", e.URL, e.ContentType, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// RequestError es una solicitud que no obtuvo respuesta (error de red o timeout)
+type RequestError struct {
+	URL string
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("error al realizar la solicitud a %s: %v", e.URL, e.Err)
+}
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// RetryError indica que se agotaron los intentos; Err es el error del último
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("OpenF1 sigue fallando después de %d intentos: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// Get pide path (con su query, por ejemplo "/v1/laps?session_key=9472") y devuelve
+// los registros de la respuesta sin decodificar (ver Decode)
+func (c *Client) Get(ctx context.Context, path string) ([]json.RawMessage, error) {
+	maxRetries := c.MaxRetries
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		var data []json.RawMessage
+		data, err = c.get(ctx, path)
+		if err == nil {
+			return data, nil
+		}
+		if !retryable(ctx, err) {
+			return nil, err
+		}
+		if attempt >= maxRetries {
+			return nil, &RetryError{Attempts: attempt, Err: err}
+		}
+
+		wait := backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests {
+			// 429: todas las goroutines esperan antes de la próxima solicitud
+			if statusErr.RetryAfter > wait {
+				wait = statusErr.RetryAfter
+			}
+			log.Printf("OpenF1 limitó la solicitud %s (intento %d). Esperando %v antes de reintentar...", path, attempt, wait.Round(time.Millisecond))
+			c.limiter.Pause(wait)
+			continue
+		}
+
+		log.Printf("Intento %d fallido para %s: %v. Esperando %v antes de reintentar...", attempt, path, err, wait.Round(time.Millisecond))
+		if err := c.limiter.clock.Sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// get hace una solicitud y decodifica la respuesta
+func (c *Client) get(ctx context.Context, path string) ([]json.RawMessage, error) {
+	url := c.BaseURL + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error al armar la solicitud: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	// Realizar la solicitud HTTP GET
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, &RequestError{URL: url, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		io.Copy(io.Discard, resp.Body)
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			URL:        url,
+			Body:       strings.TrimSpace(string(body)),
+			RetryAfter: retryAfter(resp.Header.Get("Retry-After"), c.limiter.clock.Now()),
+		}
+	}
+
+	// Leer el cuerpo de la respuesta
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &RequestError{URL: url, Err: fmt.Errorf("error al leer la respuesta: %v", err)}
+	}
+
+	// Parsear el JSON: una lista de registros, que se validan uno por uno al decodificarlos
+	var data []json.RawMessage
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, &DecodeError{URL: url, ContentType: resp.Header.Get("Content-Type"), Err: err}
+	}
+	return data, nil
+}
+
+// retryable indica si vale la pena reintentar después de err: errores de red (salvo que
+// se haya cancelado ctx), 429 y 5xx
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	var requestErr *RequestError
+	return errors.As(err, &requestErr)
+}
+
+// backoff devuelve la espera después del intento attempt: BaseBackoff duplicado en cada
+// intento hasta MaxBackoff, con un jitter que la reduce hasta la mitad para que las
+// goroutines que fallaron juntas no reintenten todas a la vez
+func backoff(attempt int) time.Duration {
+	wait := MaxBackoff
+	if attempt < 16 {
+		if d := BaseBackoff << (attempt - 1); d < MaxBackoff {
+			wait = d
+		}
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// retryAfter interpreta el header Retry-After, que puede ser una cantidad de segundos
+// o una fecha HTTP. Si falta o es inválido devuelve 0. Nunca devuelve menos de un
+// segundo si vino, para no volver a pedir enseguida a una API que está limitando.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && seconds >= 0 {
+		if seconds < 1 {
+			return time.Second
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+		return time.Second
+	}
+	return 0
+}
@@ -0,0 +1,307 @@
+package openf1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock avanza al dormir en vez de esperar, y registra cada espera
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.sleeps = append(c.sleeps, d)
+	return ctx.Err()
+}
+
+func (c *fakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+// response es lo que responde el servidor de prueba a una solicitud
+type response struct {
+	status      int
+	contentType string
+	header      map[string]string
+	body        string
+}
+
+// testServer responde en orden cada elemento de responses (el último se repite) y guarda
+// la hora de clock a la que llegó cada solicitud
+type testServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []time.Time
+}
+
+func newTestServer(t *testing.T, clock *fakeClock, responses ...response) *testServer {
+	t.Helper()
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, clock.Now())
+		resp := responses[len(responses)-1]
+		if len(s.requests) <= len(responses) {
+			resp = responses[len(s.requests)-1]
+		}
+		s.mu.Unlock()
+
+		if resp.contentType == "" {
+			resp.contentType = "application/json"
+		}
+		w.Header().Set("Content-Type", resp.contentType)
+		for name, value := range resp.header {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(resp.status)
+		w.Write([]byte(resp.body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) Requests() []time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]time.Time(nil), s.requests...)
+}
+
+// newTestClient crea un Client sin límite de solicitudes que usa clock
+func newTestClient(baseURL string, maxRetries int, clock *fakeClock) *Client {
+	c := New(baseURL, 0, maxRetries)
+	c.limiter.clock = clock
+	return c
+}
+
+func TestGetRetriesServerErrors(t *testing.T) {
+	clock := newFakeClock()
+	server := newTestServer(t, clock,
+		response{status: 503, body: "no disponible"},
+		response{status: 200, body: `[{"driver_number": 1}, {"driver_number": 16}]`},
+	)
+	c := newTestClient(server.URL, 3, clock)
+
+	data, err := c.Get(context.Background(), "/v1/drivers?session_key=9472")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 {
+		t.Errorf("Get devolvió %d registros, se esperaban 2", len(data))
+	}
+	if requests := server.Requests(); len(requests) != 2 {
+		t.Errorf("%d solicitudes, se esperaban 2", len(requests))
+	}
+	// Una espera de backoff(1): entre la mitad de BaseBackoff y BaseBackoff
+	if sleeps := clock.Sleeps(); len(sleeps) != 1 || sleeps[0] < BaseBackoff/2 || sleeps[0] > BaseBackoff {
+		t.Errorf("esperas %v, se esperaba una entre %v y %v", sleeps, BaseBackoff/2, BaseBackoff)
+	}
+}
+
+func TestGetHonoursRetryAfter(t *testing.T) {
+	start := newFakeClock().Now()
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+	}{
+		{"segundos", "7", 7 * time.Second},
+		{"fecha HTTP", start.Add(12 * time.Second).Format(http.TimeFormat), 12 * time.Second},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock()
+			server := newTestServer(t, clock,
+				response{status: 429, header: map[string]string{"Retry-After": tc.retryAfter}, body: "too many requests"},
+				response{status: 200, body: `[]`},
+			)
+			c := newTestClient(server.URL, 3, clock)
+
+			if _, err := c.Get(context.Background(), "/v1/laps?session_key=9472"); err != nil {
+				t.Fatal(err)
+			}
+			requests := server.Requests()
+			if len(requests) != 2 {
+				t.Fatalf("%d solicitudes, se esperaban 2", len(requests))
+			}
+			if waited := requests[1].Sub(requests[0]); waited != tc.want {
+				t.Errorf("el reintento fue %v después, se esperaba %v", waited, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetDoesNotRetryClientErrors(t *testing.T) {
+	clock := newFakeClock()
+	server := newTestServer(t, clock, response{status: 404, body: `{"detail": "Not Found"}`})
+	c := newTestClient(server.URL, 5, clock)
+
+	_, err := c.Get(context.Background(), "/v1/unknown")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 404 {
+		t.Fatalf("Get = %v, se esperaba un *StatusError 404", err)
+	}
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		t.Errorf("Get = %v, no se esperaba un *RetryError", err)
+	}
+	if statusErr.Body != `{"detail": "Not Found"}` || statusErr.URL != server.URL+"/v1/unknown" {
+		t.Errorf("StatusError = %+v", statusErr)
+	}
+	if requests := server.Requests(); len(requests) != 1 {
+		t.Errorf("%d solicitudes, se esperaba 1", len(requests))
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 0 {
+		t.Errorf("esperas %v, no se esperaba ninguna", sleeps)
+	}
+}
+
+func TestGetDecodeError(t *testing.T) {
+	clock := newFakeClock()
+	server := newTestServer(t, clock, response{status: 200, contentType: "text/html", body: "<html><body>Mantenimiento</body></html>"})
+	c := newTestClient(server.URL, 3, clock)
+
+	_, err := c.Get(context.Background(), "/v1/laps?session_key=9472")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Get = %v, se esperaba un *DecodeError", err)
+	}
+	if decodeErr.ContentType != "text/html" {
+		t.Errorf("ContentType = %q, se esperaba text/html", decodeErr.ContentType)
+	}
+	if requests := server.Requests(); len(requests) != 1 {
+		t.Errorf("%d solicitudes, se esperaba 1", len(requests))
+	}
+}
+
+func TestGetRetryError(t *testing.T) {
+	clock := newFakeClock()
+	server := newTestServer(t, clock, response{status: 502, body: "bad gateway"})
+	c := newTestClient(server.URL, 3, clock)
+
+	_, err := c.Get(context.Background(), "/v1/position?session_key=9472")
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 {
+		t.Fatalf("Get = %v, se esperaba un *RetryError de 3 intentos", err)
+	}
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 502 {
+		t.Errorf("Get = %v, se esperaba que envolviera el *StatusError 502", err)
+	}
+	if requests := server.Requests(); len(requests) != 3 {
+		t.Errorf("%d solicitudes, se esperaban 3", len(requests))
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 2 {
+		t.Errorf("esperas %v, se esperaban 2", sleeps)
+	}
+}
+
+func TestGetRequestError(t *testing.T) {
+	clock := newFakeClock()
+	server := newTestServer(t, clock, response{status: 200, body: `[]`})
+	server.Close()
+	c := newTestClient(server.URL, 2, clock)
+
+	_, err := c.Get(context.Background(), "/v1/laps?session_key=9472")
+	var retryErr *RetryError
+	var requestErr *RequestError
+	if !errors.As(err, &retryErr) || !errors.As(err, &requestErr) {
+		t.Fatalf("Get = %v, se esperaba un *RetryError que envolviera un *RequestError", err)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"429", context.Background(), &StatusError{StatusCode: 429}, true},
+		{"500", context.Background(), &StatusError{StatusCode: 500}, true},
+		{"503", context.Background(), &StatusError{StatusCode: 503}, true},
+		{"400", context.Background(), &StatusError{StatusCode: 400}, false},
+		{"404", context.Background(), &StatusError{StatusCode: 404}, false},
+		{"red", context.Background(), &RequestError{Err: errors.New("connection refused")}, true},
+		{"red con ctx cancelado", canceled, &RequestError{Err: context.Canceled}, false},
+		{"JSON inválido", context.Background(), &DecodeError{Err: errors.New("invalid character")}, false},
+		{"otro error", context.Background(), errors.New("error al armar la solicitud"), false},
+	}
+	for _, tc := range tests {
+		if got := retryable(tc.ctx, tc.err); got != tc.want {
+			t.Errorf("retryable(%s) = %v, se esperaba %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, BaseBackoff},
+		{2, 2 * BaseBackoff},
+		{3, 4 * BaseBackoff},
+		{7, MaxBackoff},
+		{16, MaxBackoff},
+		{100, MaxBackoff},
+	}
+	for _, tc := range tests {
+		for i := 0; i < 20; i++ {
+			if wait := backoff(tc.attempt); wait < tc.max/2 || wait > tc.max {
+				t.Errorf("backoff(%d) = %v, se esperaba entre %v y %v", tc.attempt, wait, tc.max/2, tc.max)
+				break
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{" 5 ", 5 * time.Second},
+		{"0", time.Second},
+		{"-1", 0},
+		{"pronto", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), time.Second},
+	}
+	for _, tc := range tests {
+		if got := retryAfter(tc.header, now); got != tc.want {
+			t.Errorf("retryAfter(%q) = %v, se esperaba %v", tc.header, got, tc.want)
+		}
+	}
+}
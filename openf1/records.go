@@ -0,0 +1,209 @@
+package openf1
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// Los tipos de este archivo son los registros de cada recurso de OpenF1. Todos los campos
+// son punteros porque OpenF1 puede mandar cualquiera en null: nil significa que el dato
+// no vino. Validate rechaza los registros a los que les falta algo que la base exige o
+// que traen valores imposibles; los campos opcionales siguen pudiendo ser nil y se
+// guardan como NULL.
+
+// Driver es un registro de /v1/drivers. CountryCode es opcional: OpenF1 no lo informa
+// para algunos pilotos de reserva.
+type Driver struct {
+	DriverNumber *int    `json:"driver_number"`
+	FirstName    *string `json:"first_name"`
+	LastName     *string `json:"last_name"`
+	NameAcronym  *string `json:"name_acronym"`
+	TeamName     *string `json:"team_name"`
+	CountryCode  *string `json:"country_code"`
+}
+
+func (d Driver) Validate() error {
+	return firstError(
+		requirePositive("driver_number", d.DriverNumber),
+		requireText("first_name", d.FirstName),
+		requireText("last_name", d.LastName),
+		requireText("name_acronym", d.NameAcronym),
+		requireText("team_name", d.TeamName),
+	)
+}
+
+// Session es un registro de /v1/sessions
+type Session struct {
+	SessionKey       *int    `json:"session_key"`
+	SessionName      *string `json:"session_name"`
+	SessionType      *string `json:"session_type"`
+	Location         *string `json:"location"`
+	CountryName      *string `json:"country_name"`
+	Year             *int    `json:"year"`
+	CircuitKey       *int    `json:"circuit_key"`
+	CircuitShortName *string `json:"circuit_short_name"`
+	DateStart        *string `json:"date_start"`
+}
+
+func (s Session) Validate() error {
+	return firstError(
+		requirePositive("session_key", s.SessionKey),
+		requireText("session_name", s.SessionName),
+		requireText("session_type", s.SessionType),
+		requireText("location", s.Location),
+		requireText("country_name", s.CountryName),
+		requirePositive("year", s.Year),
+		requirePositive("circuit_key", s.CircuitKey),
+		requireText("circuit_short_name", s.CircuitShortName),
+		requireDate("date_start", s.DateStart),
+	)
+}
+
+// Position es un registro de /v1/position
+type Position struct {
+	DriverNumber *int    `json:"driver_number"`
+	SessionKey   *int    `json:"session_key"`
+	Position     *int    `json:"position"`
+	Date         *string `json:"date"`
+}
+
+func (p Position) Validate() error {
+	return firstError(
+		requirePositive("driver_number", p.DriverNumber),
+		requirePositive("position", p.Position),
+		requireDate("date", p.Date),
+	)
+}
+
+// Lap es un registro de /v1/laps. Solo driver_number y lap_number son obligatorios.
+type Lap struct {
+	DriverNumber    *int     `json:"driver_number"`
+	SessionKey      *int     `json:"session_key"`
+	LapNumber       *int     `json:"lap_number"`
+	LapDuration     *float64 `json:"lap_duration"`
+	DurationSector1 *float64 `json:"duration_sector_1"`
+	DurationSector2 *float64 `json:"duration_sector_2"`
+	DurationSector3 *float64 `json:"duration_sector_3"`
+	StSpeed         *float64 `json:"st_speed"`
+	DateStart       *string  `json:"date_start"`
+}
+
+func (l Lap) Validate() error {
+	return firstError(
+		requirePositive("driver_number", l.DriverNumber),
+		requirePositive("lap_number", l.LapNumber),
+		optionalPositive("lap_duration", l.LapDuration),
+		optionalPositive("duration_sector_1", l.DurationSector1),
+		optionalPositive("duration_sector_2", l.DurationSector2),
+		optionalPositive("duration_sector_3", l.DurationSector3),
+		optionalPositive("st_speed", l.StSpeed),
+		optionalDate("date_start", l.DateStart),
+	)
+}
+
+// Rejection es un registro descartado al decodificar una respuesta de OpenF1
+type Rejection struct {
+	// Source es la solicitud de la que vino, por ejemplo "/v1/laps?session_key=9472"
+	Source string
+	// Index es la posición del registro en la respuesta
+	Index int
+	// Record es el JSON original del registro
+	Record string
+	Reason string
+}
+
+// Decode decodifica los registros de la respuesta de source (ver Client.Get) como T, por
+// ejemplo Decode[Lap]. Los registros inválidos no se devuelven: se informan en el log y
+// en la lista de rechazos. filters son reglas propias de quien llama que se aplican a los
+// registros válidos; los que no las cumplen también quedan en la lista de rechazos.
+func Decode[T validator](source string, raw []json.RawMessage, filters ...func(T) error) ([]T, []Rejection) {
+	var records []T
+	var rejected []Rejection
+	for i, data := range raw {
+		var record T
+		err := unmarshal(data, &record)
+		for _, filter := range filters {
+			if err != nil {
+				break
+			}
+			err = filter(record)
+		}
+		if err != nil {
+			log.Printf("Registro %d de %s descartado: %v", i, source, err)
+			rejected = append(rejected, Rejection{Source: source, Index: i, Record: string(data), Reason: err.Error()})
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, rejected
+}
+
+type validator interface {
+	Validate() error
+}
+
+// unmarshal decodifica un registro en record y lo valida. Un campo con un tipo distinto
+// del esperado (un texto en lugar de un número, un decimal en un entero) es un error.
+func unmarshal[T validator](data json.RawMessage, record *T) error {
+	if err := json.Unmarshal(data, record); err != nil {
+		return fmt.Errorf("registro con formato inválido: %v", err)
+	}
+	return (*record).Validate()
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func requirePositive(field string, value *int) error {
+	if value == nil {
+		return fmt.Errorf("falta %s", field)
+	}
+	if *value <= 0 {
+		return fmt.Errorf("%s inválido: %d", field, *value)
+	}
+	return nil
+}
+
+func requireText(field string, value *string) error {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return fmt.Errorf("falta %s", field)
+	}
+	return nil
+}
+
+func requireDate(field string, value *string) error {
+	if value == nil {
+		return fmt.Errorf("falta %s", field)
+	}
+	return optionalDate(field, value)
+}
+
+func optionalDate(field string, value *string) error {
+	if value == nil {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, *value); err != nil {
+		return fmt.Errorf("%s no es una fecha válida: %q", field, *value)
+	}
+	return nil
+}
+
+func optionalPositive(field string, value *float64) error {
+	if value == nil {
+		return nil
+	}
+	if *value <= 0 || math.IsInf(*value, 0) {
+		return fmt.Errorf("%s inválido: %v", field, *value)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package openf1
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func rawRecords(records ...string) []json.RawMessage {
+	raw := make([]json.RawMessage, len(records))
+	for i, record := range records {
+		raw[i] = json.RawMessage(record)
+	}
+	return raw
+}
+
+func TestDecodeDrivers(t *testing.T) {
+	raw := rawRecords(
+		`{"driver_number": 1, "first_name": "Max", "last_name": "Verstappen", "name_acronym": "VER", "team_name": "Red Bull Racing", "country_code": "NED"}`,
+		`{"driver_number": 38, "first_name": "Oliver", "last_name": "Bearman", "name_acronym": "BEA", "team_name": "Ferrari", "country_code": null}`,
+		`{"driver_number": 2, "first_name": "Logan", "last_name": "Sargeant", "name_acronym": "SAR", "team_name": "Williams"}`,
+		`{"driver_number": null, "first_name": "Sin", "last_name": "Número", "name_acronym": "SIN", "team_name": "Haas", "country_code": "USA"}`,
+		`{"driver_number": 3, "first_name": "Daniel", "last_name": "Ricciardo", "name_acronym": "RIC", "team_name": null, "country_code": "AUS"}`,
+		`{"driver_number": "4", "first_name": "Lando", "last_name": "Norris", "name_acronym": "NOR", "team_name": "McLaren", "country_code": "GBR"}`,
+	)
+
+	drivers, rejected := Decode[Driver]("/v1/drivers", raw)
+
+	numbers := []int{}
+	for _, d := range drivers {
+		numbers = append(numbers, *d.DriverNumber)
+	}
+	if want := []int{1, 38, 2}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("pilotos decodificados %v, se esperaban %v", numbers, want)
+	}
+	if len(drivers) == 3 && (drivers[1].CountryCode != nil || drivers[2].CountryCode != nil) {
+		t.Errorf("country_code null o ausente = %v, %v; se esperaba nil", drivers[1].CountryCode, drivers[2].CountryCode)
+	}
+
+	wantRejected := []struct {
+		index  int
+		reason string
+	}{
+		{3, "falta driver_number"},
+		{4, "falta team_name"},
+		{5, "registro con formato inválido: json: cannot unmarshal string into Go struct field Driver.driver_number of type int"},
+	}
+	if len(rejected) != len(wantRejected) {
+		t.Fatalf("%d rechazos, se esperaban %d: %+v", len(rejected), len(wantRejected), rejected)
+	}
+	for i, want := range wantRejected {
+		r := rejected[i]
+		if r.Source != "/v1/drivers" || r.Index != want.index || r.Reason != want.reason || r.Record != string(raw[want.index]) {
+			t.Errorf("rechazo %d = %+v, se esperaba el registro %d con %q", i, r, want.index, want.reason)
+		}
+	}
+}
+
+func TestDecodeLaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+		reason string
+	}{
+		{"completa", `{"driver_number": 1, "lap_number": 2, "lap_duration": 95.2, "duration_sector_1": 30.7, "duration_sector_2": 33.2, "duration_sector_3": 31.3, "st_speed": 309.5, "date_start": "2023-03-05T15:01:36.000000+00:00"}`, ""},
+		{"campos opcionales en null", `{"driver_number": 1, "lap_number": 1, "lap_duration": null, "duration_sector_1": null, "st_speed": null, "date_start": null}`, ""},
+		{"sin lap_number", `{"driver_number": 1, "lap_duration": 95.2}`, "falta lap_number"},
+		{"tiempo negativo", `{"driver_number": 1, "lap_number": 2, "lap_duration": -1}`, "lap_duration inválido: -1"},
+		{"fecha inválida", `{"driver_number": 1, "lap_number": 2, "date_start": "ayer"}`, `date_start no es una fecha válida: "ayer"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			laps, rejected := Decode[Lap]("/v1/laps?session_key=9472", rawRecords(tc.record))
+			if tc.reason == "" {
+				if len(laps) != 1 || len(rejected) != 0 {
+					t.Errorf("Decode = %d vueltas y %+v, se esperaba la vuelta sin rechazos", len(laps), rejected)
+				}
+				return
+			}
+			if len(laps) != 0 || len(rejected) != 1 || rejected[0].Reason != tc.reason {
+				t.Errorf("Decode = %d vueltas y %+v, se esperaba el rechazo %q", len(laps), rejected, tc.reason)
+			}
+		})
+	}
+}
+
+// TestDecodeFilters comprueba que los registros válidos que no pasan un filtro queden en
+// los rechazos con el motivo del filtro
+func TestDecodeFilters(t *testing.T) {
+	raw := rawRecords(
+		`{"driver_number": 1, "session_key": 9472, "position": 1, "date": "2024-03-02T15:00:00+00:00"}`,
+		`{"driver_number": 61, "session_key": 9472, "position": 20, "date": "2024-03-02T15:00:00+00:00"}`,
+		`{"driver_number": 61, "session_key": 9472, "position": 0, "date": "2024-03-02T15:00:00+00:00"}`,
+	)
+	reserved := func(p Position) error {
+		if *p.DriverNumber == 61 {
+			return errors.New("piloto de reserva")
+		}
+		return nil
+	}
+
+	positions, rejected := Decode("/v1/position?session_key=9472", raw, reserved)
+	if len(positions) != 1 || *positions[0].DriverNumber != 1 {
+		t.Errorf("Decode devolvió %d posiciones, se esperaba solo la del piloto 1", len(positions))
+	}
+	// El registro inválido se rechaza por la validación, antes de llegar al filtro
+	want := []string{"piloto de reserva", "position inválido: 0"}
+	if len(rejected) != len(want) {
+		t.Fatalf("%d rechazos, se esperaban %d: %+v", len(rejected), len(want), rejected)
+	}
+	for i, reason := range want {
+		if rejected[i].Index != i+1 || rejected[i].Reason != reason {
+			t.Errorf("rechazo %d = %+v, se esperaba el registro %d con %q", i, rejected[i], i+1, reason)
+		}
+	}
+}
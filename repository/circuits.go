@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"f1_statshub_system/models"
+	"f1_statshub_system/storage"
+)
+
+// CircuitRepo consulta los circuitos y las carreras disputadas en cada uno
+type CircuitRepo struct {
+	db *storage.DB
+}
+
+func NewCircuitRepo(db *storage.DB) *CircuitRepo {
+	return &CircuitRepo{db: db}
+}
+
+// List devuelve todos los circuitos con la cantidad de carreras ingeridas en cada uno
+func (r *CircuitRepo) List(ctx context.Context) ([]models.Circuit, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.circuit_key, c.circuit_short_name, c.location, c.country_name, c.lap_length_km,
+			(SELECT COUNT(*) FROM Session s WHERE s.circuit_key = c.circuit_key AND s.session_name = 'Race') AS races
+		FROM Circuit c
+		ORDER BY c.circuit_short_name ASC, c.circuit_key ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var circuits []models.Circuit
+	for rows.Next() {
+		var circuit models.Circuit
+		var lapLength sql.NullFloat64
+		if err := rows.Scan(&circuit.CircuitKey, &circuit.CircuitShortName, &circuit.Location, &circuit.CountryName, &lapLength, &circuit.Races); err != nil {
+			return nil, err
+		}
+		circuit.LapLengthKm = nullFloatToPtr(lapLength)
+		circuits = append(circuits, circuit)
+	}
+	return circuits, rows.Err()
+}
+
+// Get devuelve un circuito por su circuit_key, o ErrNotFound si no existe. Races queda en 0.
+func (r *CircuitRepo) Get(ctx context.Context, circuitKey int) (*models.Circuit, error) {
+	circuit := models.Circuit{CircuitKey: circuitKey}
+	var lapLength sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT circuit_short_name, location, country_name, lap_length_km
+		FROM Circuit WHERE circuit_key = ?
+	`, circuitKey).Scan(&circuit.CircuitShortName, &circuit.Location, &circuit.CountryName, &lapLength)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	circuit.LapLengthKm = nullFloatToPtr(lapLength)
+	return &circuit, nil
+}
+
+// Races devuelve las carreras disputadas en el circuito, en orden cronológico, con su
+// ganador, su vuelta rápida y la velocidad promedio (nil si no se conoce el largo de vuelta)
+func (r *CircuitRepo) Races(ctx context.Context, circuit *models.Circuit) ([]models.CircuitRace, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			s.session_key,
+			s.year,
+			s.date_start,
+			COALESCE(d.first_name || ' ' || d.last_name, ''),
+			COALESCE(d.team_name, ''),
+			(
+				SELECT MIN(lap_duration)
+				FROM FastestLap
+				WHERE session_key = s.session_key
+			) AS best_lap_duration,
+			(
+				SELECT CAST(? AS DOUBLE PRECISION) * 3600.0 / AVG(lap_duration)
+				FROM Laps
+				WHERE session_key = s.session_key
+			) AS average_speed_kmh
+		FROM Session s
+		LEFT JOIN Position p ON p.session_key = s.session_key AND p.position = 1
+		LEFT JOIN Driver d ON d.driver_number = p.driver_number
+		WHERE s.circuit_key = ? AND s.session_name = 'Race'
+		ORDER BY s.date_start ASC
+	`, circuit.LapLengthKm, circuit.CircuitKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var races []models.CircuitRace
+	for rows.Next() {
+		var race models.CircuitRace
+		var bestLap, avgSpeed sql.NullFloat64
+		if err := rows.Scan(&race.SessionKey, &race.Year, &race.DateStart, &race.Winner, &race.Team, &bestLap, &avgSpeed); err != nil {
+			return nil, err
+		}
+		race.BestLapDuration = nullFloatToPtr(bestLap)
+		race.AverageSpeedKMH = nullFloatToPtr(avgSpeed)
+		races = append(races, race)
+	}
+	return races, rows.Err()
+}
+
+// LapRecord devuelve el récord de vuelta del circuito entre todas las temporadas
+// ingeridas, o nil si todavía no tiene
+func (r *CircuitRepo) LapRecord(ctx context.Context, circuit *models.Circuit) (*models.CircuitLapRecord, error) {
+	var record models.CircuitLapRecord
+	err := r.db.QueryRowContext(ctx, `
+		SELECT d.first_name || ' ' || d.last_name, r.lap_duration, r.session_key, s.year
+		FROM CircuitLapRecord r
+		JOIN Session s ON s.session_key = r.session_key
+		JOIN Driver d ON d.driver_number = r.driver_number
+		WHERE r.circuit_key = ?
+	`, circuit.CircuitKey).Scan(&record.Driver, &record.LapDuration, &record.SessionKey, &record.Year)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if circuit.LapLengthKm != nil {
+		speed := *circuit.LapLengthKm * 3600.0 / record.LapDuration
+		record.AverageSpeedKMH = &speed
+	}
+	return &record, nil
+}
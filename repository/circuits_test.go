@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"f1_statshub_system/models"
+)
+
+func TestCircuitRepoList(t *testing.T) {
+	repo := NewCircuitRepo(seeded(t))
+
+	circuits, err := repo.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.Circuit{
+		{CircuitKey: 149, CircuitShortName: "Jeddah", Location: "Jeddah", CountryName: "Saudi Arabia", Races: 1},
+		{CircuitKey: 63, CircuitShortName: "Sakhir", Location: "Sakhir", CountryName: "Bahrain", LapLengthKm: ptr(5.412), Races: 2},
+	}
+	if !reflect.DeepEqual(circuits, want) {
+		t.Errorf("List = %+v, se esperaba %+v", circuits, want)
+	}
+}
+
+func TestCircuitRepoGet(t *testing.T) {
+	repo := NewCircuitRepo(seeded(t))
+
+	tests := []struct {
+		circuitKey int
+		want       *models.Circuit
+		err        error
+	}{
+		{63, &models.Circuit{CircuitKey: 63, CircuitShortName: "Sakhir", Location: "Sakhir", CountryName: "Bahrain", LapLengthKm: ptr(5.412)}, nil},
+		{149, &models.Circuit{CircuitKey: 149, CircuitShortName: "Jeddah", Location: "Jeddah", CountryName: "Saudi Arabia"}, nil},
+		{7, nil, ErrNotFound},
+	}
+	for _, tc := range tests {
+		circuit, err := repo.Get(context.Background(), tc.circuitKey)
+		if err != tc.err {
+			t.Fatalf("Get(%d) error = %v, se esperaba %v", tc.circuitKey, err, tc.err)
+		}
+		if !reflect.DeepEqual(circuit, tc.want) {
+			t.Errorf("Get(%d) = %+v, se esperaba %+v", tc.circuitKey, circuit, tc.want)
+		}
+	}
+}
+
+func TestCircuitRepoRaces(t *testing.T) {
+	repo := NewCircuitRepo(seeded(t))
+
+	// La velocidad promedio es el largo de vuelta sobre el promedio de las vueltas con tiempo
+	tests := []struct {
+		circuit models.Circuit
+		want    []models.CircuitRace
+	}{
+		{models.Circuit{CircuitKey: 63, LapLengthKm: ptr(5.412)}, []models.CircuitRace{
+			{SessionKey: 7953, Year: 2023, DateStart: "2023-03-05T15:00:00+00:00", Winner: "Max Verstappen", Team: "Red Bull Racing", BestLapDuration: ptr(95.2), AverageSpeedKMH: ptr(5.412 * 3600 / (576.1 / 6))},
+			{SessionKey: 9472, Year: 2024, DateStart: "2024-03-02T15:00:00+00:00", Winner: "Max Verstappen", Team: "Red Bull Racing", BestLapDuration: ptr(94.5), AverageSpeedKMH: ptr(5.412 * 3600 / (765.6 / 8))},
+		}},
+		{models.Circuit{CircuitKey: 149}, []models.CircuitRace{
+			{SessionKey: 9480, Year: 2024, DateStart: "2024-03-09T17:00:00+00:00", Winner: "Charles Leclerc", Team: "Ferrari", BestLapDuration: ptr(89.9)},
+		}},
+		{models.Circuit{CircuitKey: 7}, nil},
+	}
+	for _, tc := range tests {
+		races, err := repo.Races(context.Background(), &tc.circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(races) != len(tc.want) {
+			t.Fatalf("Races(%d) = %+v, se esperaba %+v", tc.circuit.CircuitKey, races, tc.want)
+		}
+		for i, race := range races {
+			want := tc.want[i]
+			speedOK := approxPtr(race.AverageSpeedKMH, want.AverageSpeedKMH)
+			race.AverageSpeedKMH, want.AverageSpeedKMH = nil, nil
+			if !reflect.DeepEqual(race, want) || !speedOK {
+				t.Errorf("Races(%d)[%d] = %+v, se esperaba %+v", tc.circuit.CircuitKey, i, races[i], tc.want[i])
+			}
+		}
+	}
+}
+
+func TestCircuitRepoLapRecord(t *testing.T) {
+	repo := NewCircuitRepo(seeded(t))
+
+	tests := []struct {
+		circuit models.Circuit
+		want    *models.CircuitLapRecord
+	}{
+		{models.Circuit{CircuitKey: 63, LapLengthKm: ptr(5.412)}, &models.CircuitLapRecord{Driver: "Max Verstappen", LapDuration: 94.5, SessionKey: 9472, Year: 2024, AverageSpeedKMH: ptr(5.412 * 3600 / 94.5)}},
+		{models.Circuit{CircuitKey: 149}, &models.CircuitLapRecord{Driver: "Max Verstappen", LapDuration: 89.9, SessionKey: 9480, Year: 2024}},
+		{models.Circuit{CircuitKey: 7}, nil},
+	}
+	for _, tc := range tests {
+		record, err := repo.LapRecord(context.Background(), &tc.circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(record, tc.want) {
+			t.Errorf("LapRecord(%d) = %+v, se esperaba %+v", tc.circuit.CircuitKey, record, tc.want)
+		}
+	}
+}
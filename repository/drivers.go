@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"f1_statshub_system/models"
+	"f1_statshub_system/storage"
+)
+
+// DriverRepo consulta la tabla Driver
+type DriverRepo struct {
+	db *storage.DB
+}
+
+func NewDriverRepo(db *storage.DB) *DriverRepo {
+	return &DriverRepo{db: db}
+}
+
+// DriverFilter filtra y pagina el listado de pilotos. Team y Country vacíos no filtran
+// (la comparación no distingue mayúsculas). OrderBy se concatena en la consulta, así que
+// debe salir de una lista de columnas permitidas, nunca directo de la solicitud.
+type DriverFilter struct {
+	Team    string
+	Country string
+	OrderBy string
+	Limit   int
+	Offset  int
+}
+
+// List devuelve la página de pilotos pedida y el total que cumple los filtros
+func (r *DriverRepo) List(ctx context.Context, f DriverFilter) ([]models.Driver, int, error) {
+	filter := `
+		FROM Driver
+		WHERE (? = '' OR LOWER(team_name) = LOWER(?))
+		  AND (? = '' OR LOWER(country_code) = LOWER(?))
+	`
+	filterArgs := []interface{}{f.Team, f.Team, f.Country, f.Country}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) "+filter, filterArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT first_name, last_name, driver_number, team_name, country_code
+	`+filter+`
+		ORDER BY `+f.OrderBy+`, driver_number ASC
+		LIMIT ? OFFSET ?
+	`, append(filterArgs, f.Limit, f.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	drivers := []models.Driver{}
+	for rows.Next() {
+		var d models.Driver
+		if err := rows.Scan(&d.FirstName, &d.LastName, &d.DriverNumber, &d.TeamName, &d.CountryCode); err != nil {
+			return nil, 0, err
+		}
+		drivers = append(drivers, d)
+	}
+	return drivers, total, rows.Err()
+}
+
+// Exists indica si hay un piloto con ese número
+func (r *DriverRepo) Exists(ctx context.Context, driverNumber int) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM Driver WHERE driver_number = ?`, driverNumber).Scan(&count)
+	return count > 0, err
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"f1_statshub_system/models"
+)
+
+func TestDriverRepoList(t *testing.T) {
+	repo := NewDriverRepo(seeded(t))
+
+	tests := []struct {
+		name    string
+		filter  DriverFilter
+		numbers []int
+		total   int
+	}{
+		{"todos", DriverFilter{OrderBy: "driver_number ASC", Limit: 100}, []int{1, 4, 16}, 3},
+		{"equipo sin distinguir mayúsculas", DriverFilter{Team: "ferrari", OrderBy: "driver_number ASC", Limit: 100}, []int{16}, 1},
+		{"país", DriverFilter{Country: "gbr", OrderBy: "driver_number ASC", Limit: 100}, []int{4}, 1},
+		{"orden descendente", DriverFilter{OrderBy: "last_name DESC", Limit: 100}, []int{1, 4, 16}, 3},
+		{"página", DriverFilter{OrderBy: "driver_number ASC", Limit: 1, Offset: 1}, []int{4}, 3},
+		{"página vacía", DriverFilter{OrderBy: "driver_number ASC", Limit: 10, Offset: 3}, []int{}, 3},
+		{"sin resultados", DriverFilter{Team: "Williams", OrderBy: "driver_number ASC", Limit: 100}, []int{}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			drivers, total, err := repo.List(context.Background(), tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			numbers := []int{}
+			for _, d := range drivers {
+				numbers = append(numbers, d.DriverNumber)
+			}
+			if !reflect.DeepEqual(numbers, tc.numbers) || total != tc.total {
+				t.Errorf("List = %v (total %d), se esperaba %v (total %d)", numbers, total, tc.numbers, tc.total)
+			}
+		})
+	}
+
+	drivers, _, err := repo.List(context.Background(), DriverFilter{Team: "McLaren", OrderBy: "driver_number ASC", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.Driver{{FirstName: "Lando", LastName: "Norris", DriverNumber: 4, TeamName: "McLaren", CountryCode: ptr("GBR")}}
+	if !reflect.DeepEqual(drivers, want) {
+		t.Errorf("List = %+v, se esperaba %+v", drivers, want)
+	}
+}
+
+// TestDriverRepoListWithoutCountry comprueba que un piloto sin country_code se liste con
+// CountryCode nil y no aparezca al filtrar por país
+func TestDriverRepoListWithoutCountry(t *testing.T) {
+	db := seeded(t)
+	_, err := db.Exec(`INSERT INTO Driver (driver_number, first_name, last_name, name_acronym, team_name, country_code)
+		VALUES (38, 'Oliver', 'Bearman', 'BEA', 'Ferrari', NULL)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := NewDriverRepo(db)
+
+	drivers, total, err := repo.List(context.Background(), DriverFilter{Team: "Ferrari", OrderBy: "driver_number ASC", Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.Driver{
+		{FirstName: "Charles", LastName: "Leclerc", DriverNumber: 16, TeamName: "Ferrari", CountryCode: ptr("MON")},
+		{FirstName: "Oliver", LastName: "Bearman", DriverNumber: 38, TeamName: "Ferrari"},
+	}
+	if !reflect.DeepEqual(drivers, want) || total != 2 {
+		t.Errorf("List = %+v (total %d), se esperaba %+v (total 2)", drivers, total, want)
+	}
+
+	_, total, err = repo.List(context.Background(), DriverFilter{Country: "MON", OrderBy: "driver_number ASC", Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Errorf("List por país = %d pilotos, se esperaba 1", total)
+	}
+}
+
+func TestDriverRepoExists(t *testing.T) {
+	repo := NewDriverRepo(seeded(t))
+
+	tests := []struct {
+		driverNumber int
+		exists       bool
+	}{
+		{1, true},
+		{16, true},
+		{99, false},
+	}
+	for _, tc := range tests {
+		exists, err := repo.Exists(context.Background(), tc.driverNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != tc.exists {
+			t.Errorf("Exists(%d) = %v, se esperaba %v", tc.driverNumber, exists, tc.exists)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"f1_statshub_system/models"
+	"f1_statshub_system/storage"
+)
+
+// LapRepo consulta las vueltas de cada carrera (Laps, FastestLap, CircuitLapRecord
+// y la vista LapPosition)
+type LapRepo struct {
+	db *storage.DB
+}
+
+func NewLapRepo(db *storage.DB) *LapRepo {
+	return &LapRepo{db: db}
+}
+
+// FastestLap devuelve la vuelta rápida de una carrera: la primera en marcarse, con los
+// demás pilotos que la igualaron en TiedDrivers. Si la carrera no tiene vueltas
+// devuelve una vuelta vacía sin error.
+func (r *LapRepo) FastestLap(ctx context.Context, sessionKey int) (models.RaceFastestLap, error) {
+	var fastest models.RaceFastestLap
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT d.first_name || ' ' || d.last_name, fl.lap_duration, fl.duration_sector_1, fl.duration_sector_2, fl.duration_sector_3
+		FROM FastestLap fl
+		JOIN Driver d ON d.driver_number = fl.driver_number
+		WHERE fl.session_key = ?
+		ORDER BY fl.date_start IS NULL, fl.date_start ASC
+	`, sessionKey)
+	if err != nil {
+		return fastest, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var driver string
+		var duration float64
+		var s1, s2, s3 sql.NullFloat64
+		if err := rows.Scan(&driver, &duration, &s1, &s2, &s3); err != nil {
+			return fastest, err
+		}
+		if fastest.Driver == "" {
+			fastest = models.RaceFastestLap{
+				Driver:    driver,
+				TotalTime: duration,
+				Sector1:   nullFloatToPtr(s1),
+				Sector2:   nullFloatToPtr(s2),
+				Sector3:   nullFloatToPtr(s3),
+			}
+			continue
+		}
+		fastest.TiedDrivers = append(fastest.TiedDrivers, driver)
+	}
+	return fastest, rows.Err()
+}
+
+// MaxSpeed devuelve la mayor velocidad en la trampa de velocidad de una carrera. Si la
+// carrera no tiene vueltas con velocidad registrada devuelve un valor vacío sin error.
+// Hay que excluir los NULL: PostgreSQL los ordena primero en un ORDER BY ... DESC.
+func (r *LapRepo) MaxSpeed(ctx context.Context, sessionKey int) (models.RaceMaxSpeed, error) {
+	var speed models.RaceMaxSpeed
+	err := r.db.QueryRowContext(ctx, `
+		SELECT d.first_name || ' ' || d.last_name, l.st_speed
+		FROM Laps l
+		JOIN Driver d ON d.driver_number = l.driver_number
+		WHERE l.session_key = ? AND l.st_speed IS NOT NULL
+		ORDER BY l.st_speed DESC
+		LIMIT 1
+	`, sessionKey).Scan(&speed.Driver, &speed.SpeedKMH)
+	if err == sql.ErrNoRows {
+		return models.RaceMaxSpeed{}, nil
+	}
+	return speed, err
+}
+
+// LapsLed devuelve cuántas vueltas lideró cada piloto en una carrera, de más a menos
+func (r *LapRepo) LapsLed(ctx context.Context, sessionKey int) ([]models.DriverLapsLed, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT d.first_name || ' ' || d.last_name, d.driver_number, COUNT(*) AS laps
+		FROM LapPosition lp
+		JOIN Driver d ON d.driver_number = lp.driver_number
+		WHERE lp.session_key = ? AND lp.position = 1
+		GROUP BY d.driver_number, d.first_name, d.last_name
+		ORDER BY laps DESC
+	`, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var led []models.DriverLapsLed
+	for rows.Next() {
+		var l models.DriverLapsLed
+		if err := rows.Scan(&l.Driver, &l.DriverNumber, &l.Laps); err != nil {
+			return nil, err
+		}
+		led = append(led, l)
+	}
+	return led, rows.Err()
+}
+
+// LeadChanges cuenta las vueltas en que el líder difiere del de la vuelta anterior. Si en
+// una vuelta figuran dos pilotos primeros (cada uno toma su posición al terminar su propia
+// vuelta) el líder es el del registro de PositionHistory más reciente.
+func (r *LapRepo) LeadChanges(ctx context.Context, sessionKey int) (int, error) {
+	var changes int
+	err := r.db.QueryRowContext(ctx, `
+		WITH lap_leaders AS (
+			SELECT
+				lap_number,
+				driver_number,
+				ROW_NUMBER() OVER (
+					PARTITION BY lap_number
+					ORDER BY position_date DESC, driver_number ASC
+				) AS leader_rank
+			FROM LapPosition
+			WHERE session_key = ? AND position = 1
+		),
+		leaders AS (
+			SELECT
+				driver_number,
+				LAG(driver_number) OVER (ORDER BY lap_number) AS previous_leader
+			FROM lap_leaders
+			WHERE leader_rank = 1
+		)
+		SELECT COUNT(*)
+		FROM leaders
+		WHERE previous_leader IS NOT NULL AND previous_leader != driver_number
+	`, sessionKey).Scan(&changes)
+	return changes, err
+}
+
+// Chart devuelve la posición y el tiempo de cada piloto al final de cada vuelta,
+// agrupados por número de vuelta
+func (r *LapRepo) Chart(ctx context.Context, sessionKey int) ([]models.LapChartLap, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT lp.lap_number, lp.driver_number, d.first_name || ' ' || d.last_name, lp.position, lp.lap_duration, lp.lap_duration_estimated
+		FROM LapPosition lp
+		JOIN Driver d ON d.driver_number = lp.driver_number
+		WHERE lp.session_key = ?
+		ORDER BY lp.lap_number ASC, lp.position IS NULL, lp.position ASC
+	`, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var laps []models.LapChartLap
+	for rows.Next() {
+		var lapNumber int
+		var position sql.NullInt64
+		var lapDuration sql.NullFloat64
+		var estimated int
+		var pos models.LapChartPosition
+		if err := rows.Scan(&lapNumber, &pos.DriverNumber, &pos.Driver, &position, &lapDuration, &estimated); err != nil {
+			return nil, err
+		}
+		pos.LapDuration = nullFloatToPtr(lapDuration)
+		pos.LapDurationEstimated = estimated == 1
+
+		if len(laps) == 0 || laps[len(laps)-1].LapNumber != lapNumber {
+			laps = append(laps, models.LapChartLap{LapNumber: lapNumber})
+		}
+		lap := &laps[len(laps)-1]
+
+		if position.Valid {
+			p := int(position.Int64)
+			pos.Position = &p
+			if p == 1 {
+				lap.Leader = &models.LapLeader{
+					DriverNumber: pos.DriverNumber,
+					Driver:       pos.Driver,
+				}
+			}
+		}
+		lap.Positions = append(lap.Positions, pos)
+	}
+	return laps, rows.Err()
+}
+
+// CircuitRecord devuelve el récord de vuelta vigente del circuito de una carrera, o nil
+// si el circuito todavía no tiene récord
+func (r *LapRepo) CircuitRecord(ctx context.Context, sessionKey int) (*models.CircuitRecord, error) {
+	var record models.CircuitRecord
+	err := r.db.QueryRowContext(ctx, `
+		SELECT d.first_name || ' ' || d.last_name, r.lap_duration, r.session_key, rs.year
+		FROM Session s
+		JOIN CircuitLapRecord r ON r.circuit_key = s.circuit_key
+		JOIN Session rs ON rs.session_key = r.session_key
+		JOIN Driver d ON d.driver_number = r.driver_number
+		WHERE s.session_key = ?
+	`, sessionKey).Scan(&record.Driver, &record.LapDuration, &record.SessionKey, &record.Year)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// SetCircuitRecord indica si la vuelta rápida de una carrera mejoró la de todas las
+// carreras anteriores ingeridas en el mismo circuito
+func (r *LapRepo) SetCircuitRecord(ctx context.Context, sessionKey int) (bool, error) {
+	var sessionBest, previousBest sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			(
+				SELECT MIN(fl.lap_duration)
+				FROM FastestLap fl
+				WHERE fl.session_key = s.session_key
+			) AS session_best,
+			(
+				SELECT MIN(fl.lap_duration)
+				FROM FastestLap fl
+				JOIN Session prev ON prev.session_key = fl.session_key
+				WHERE prev.circuit_key = s.circuit_key
+				AND prev.session_name = 'Race'
+				AND prev.date_start < s.date_start
+			) AS previous_best
+		FROM Session s
+		WHERE s.session_key = ?
+	`, sessionKey).Scan(&sessionBest, &previousBest)
+	if err != nil {
+		return false, err
+	}
+	return sessionBest.Valid && previousBest.Valid && sessionBest.Float64 < previousBest.Float64, nil
+}
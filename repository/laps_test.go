@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"f1_statshub_system/models"
+)
+
+func TestLapRepoFastestLap(t *testing.T) {
+	db := seeded(t)
+	repo := NewLapRepo(db)
+
+	// Leclerc iguala después la vuelta rápida de Verstappen en 7953
+	_, err := db.Exec(`INSERT INTO FastestLap (session_key, driver_number, lap_number, lap_duration, date_start)
+		VALUES (7953, 16, 3, 95.2, '2023-03-05T15:04:00.000000+00:00')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sessionKey int
+		want       models.RaceFastestLap
+	}{
+		{9472, models.RaceFastestLap{Driver: "Max Verstappen", TotalTime: 94.5, Sector1: ptr(30.2), Sector2: ptr(33.1), Sector3: ptr(31.2)}},
+		{7953, models.RaceFastestLap{Driver: "Max Verstappen", TotalTime: 95.2, Sector1: ptr(30.7), Sector2: ptr(33.2), Sector3: ptr(31.3), TiedDrivers: []string{"Charles Leclerc"}}},
+		{1, models.RaceFastestLap{}},
+	}
+	for _, tc := range tests {
+		fastest, err := repo.FastestLap(context.Background(), tc.sessionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(fastest, tc.want) {
+			t.Errorf("FastestLap(%d) = %+v, se esperaba %+v", tc.sessionKey, fastest, tc.want)
+		}
+	}
+}
+
+func TestLapRepoMaxSpeed(t *testing.T) {
+	repo := NewLapRepo(seeded(t))
+
+	tests := []struct {
+		sessionKey int
+		want       models.RaceMaxSpeed
+	}{
+		{9472, models.RaceMaxSpeed{Driver: "Max Verstappen", SpeedKMH: 312.4}},
+		{9480, models.RaceMaxSpeed{Driver: "Max Verstappen", SpeedKMH: 323.6}},
+		{1, models.RaceMaxSpeed{}},
+	}
+	for _, tc := range tests {
+		speed, err := repo.MaxSpeed(context.Background(), tc.sessionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if speed != tc.want {
+			t.Errorf("MaxSpeed(%d) = %+v, se esperaba %+v", tc.sessionKey, speed, tc.want)
+		}
+	}
+}
+
+func TestLapRepoLapsLedAndLeadChanges(t *testing.T) {
+	repo := NewLapRepo(seeded(t))
+
+	tests := []struct {
+		sessionKey  int
+		lapsLed     []models.DriverLapsLed
+		leadChanges int
+	}{
+		{9480, []models.DriverLapsLed{{Driver: "Charles Leclerc", DriverNumber: 16, Laps: 2}, {Driver: "Max Verstappen", DriverNumber: 1, Laps: 1}}, 2},
+		{9472, []models.DriverLapsLed{{Driver: "Max Verstappen", DriverNumber: 1, Laps: 3}}, 0},
+		{1, nil, 0},
+	}
+	for _, tc := range tests {
+		led, err := repo.LapsLed(context.Background(), tc.sessionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(led, tc.lapsLed) {
+			t.Errorf("LapsLed(%d) = %+v, se esperaba %+v", tc.sessionKey, led, tc.lapsLed)
+		}
+
+		changes, err := repo.LeadChanges(context.Background(), tc.sessionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changes != tc.leadChanges {
+			t.Errorf("LeadChanges(%d) = %d, se esperaba %d", tc.sessionKey, changes, tc.leadChanges)
+		}
+	}
+}
+
+// TestLapRepoLeadChangesDuplicateLeaders cubre una vuelta con dos pilotos primeros.
+// Leclerc lidera la vuelta 1, Verstappen lo pasa a las 15:02 y Norris pasa a Verstappen a
+// las 15:03:12.05, después de que Verstappen termine la vuelta 2 (15:03:12) y antes de que
+// la termine Norris (15:03:12.1): los dos figuran primeros en la vuelta 2. Contando las dos
+// filas habría 2 o 3 cambios de líder según el orden en que salgan.
+func TestLapRepoLeadChangesDuplicateLeaders(t *testing.T) {
+	db := seeded(t)
+	for _, statement := range []string{
+		`DELETE FROM PositionHistory WHERE session_key = 9472`,
+		`INSERT INTO PositionHistory (driver_number, session_key, position, date) VALUES
+		(16, 9472, 1, '2024-03-02T15:00:00.000000+00:00'),
+		(1, 9472, 2, '2024-03-02T15:00:00.000000+00:00'),
+		(4, 9472, 3, '2024-03-02T15:00:00.000000+00:00'),
+		(1, 9472, 1, '2024-03-02T15:02:00.000000+00:00'),
+		(16, 9472, 2, '2024-03-02T15:02:00.000000+00:00'),
+		(4, 9472, 1, '2024-03-02T15:03:12.050000+00:00'),
+		(1, 9472, 2, '2024-03-02T15:03:12.050000+00:00'),
+		(16, 9472, 3, '2024-03-02T15:03:12.050000+00:00')`,
+		// Con tiempo, la vuelta 3 de Norris tiene posición
+		`UPDATE Laps SET lap_duration = 95.0 WHERE driver_number = 4 AND session_key = 9472 AND lap_number = 3`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var leaders int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM LapPosition WHERE session_key = 9472 AND lap_number = 2 AND position = 1`).Scan(&leaders); err != nil {
+		t.Fatal(err)
+	}
+	if leaders != 2 {
+		t.Fatalf("la vuelta 2 tiene %d pilotos primeros, se esperaban 2", leaders)
+	}
+
+	// El líder de la vuelta 2 es Norris, con el registro más reciente: Leclerc, Norris, Norris
+	changes, err := NewLapRepo(db).LeadChanges(context.Background(), 9472)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes != 1 {
+		t.Errorf("LeadChanges(9472) = %d, se esperaba 1", changes)
+	}
+}
+
+func TestLapRepoChart(t *testing.T) {
+	repo := NewLapRepo(seeded(t))
+
+	verstappen := &models.LapLeader{DriverNumber: 1, Driver: "Max Verstappen"}
+	position := func(p *int, driverNumber int, driver string, duration *float64, estimated bool) models.LapChartPosition {
+		return models.LapChartPosition{Position: p, DriverNumber: driverNumber, Driver: driver, LapDuration: duration, LapDurationEstimated: estimated}
+	}
+	want := []models.LapChartLap{
+		{LapNumber: 1, Leader: verstappen, Positions: []models.LapChartPosition{
+			position(ptr(1), 1, "Max Verstappen", ptr(97.0), false),
+			position(ptr(2), 16, "Charles Leclerc", ptr(97.6), false),
+			position(ptr(3), 4, "Lando Norris", ptr(97.3), false),
+		}},
+		{LapNumber: 2, Leader: verstappen, Positions: []models.LapChartPosition{
+			position(ptr(1), 1, "Max Verstappen", ptr(95.0), false),
+			position(ptr(2), 4, "Lando Norris", ptr(94.8), false),
+			position(ptr(3), 16, "Charles Leclerc", ptr(94.3), true),
+		}},
+		// Sin tiempo ni vuelta siguiente no se sabe la posición de Norris al terminar la vuelta 3
+		{LapNumber: 3, Leader: verstappen, Positions: []models.LapChartPosition{
+			position(ptr(1), 1, "Max Verstappen", ptr(94.5), false),
+			position(ptr(3), 16, "Charles Leclerc", ptr(95.1), false),
+			position(nil, 4, "Lando Norris", nil, false),
+		}},
+	}
+
+	laps, err := repo.Chart(context.Background(), 9472)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(laps, want) {
+		t.Errorf("Chart(9472) = %+v, se esperaba %+v", laps, want)
+	}
+
+	laps, err = repo.Chart(context.Background(), 1)
+	if err != nil || laps != nil {
+		t.Errorf("Chart(1) = %+v, %v; se esperaba una carrera sin vueltas", laps, err)
+	}
+}
+
+func TestLapRepoCircuitRecord(t *testing.T) {
+	repo := NewLapRepo(seeded(t))
+
+	sakhir := &models.CircuitRecord{Driver: "Max Verstappen", LapDuration: 94.5, SessionKey: 9472, Year: 2024}
+	tests := []struct {
+		sessionKey int
+		record     *models.CircuitRecord
+		newRecord  bool
+	}{
+		// 9472 mejoró la vuelta rápida de 7953, la carrera anterior en Sakhir
+		{9472, sakhir, true},
+		{7953, sakhir, false},
+		{9480, &models.CircuitRecord{Driver: "Max Verstappen", LapDuration: 89.9, SessionKey: 9480, Year: 2024}, false},
+	}
+	for _, tc := range tests {
+		record, err := repo.CircuitRecord(context.Background(), tc.sessionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(record, tc.record) {
+			t.Errorf("CircuitRecord(%d) = %+v, se esperaba %+v", tc.sessionKey, record, tc.record)
+		}
+
+		newRecord, err := repo.SetCircuitRecord(context.Background(), tc.sessionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if newRecord != tc.newRecord {
+			t.Errorf("SetCircuitRecord(%d) = %v, se esperaba %v", tc.sessionKey, newRecord, tc.newRecord)
+		}
+	}
+
+	// Los handlers verifican antes que la carrera exista
+	record, err := repo.CircuitRecord(context.Background(), 1)
+	if err != nil || record != nil {
+		t.Errorf("CircuitRecord(1) = %+v, %v; se esperaba nil sin error", record, err)
+	}
+	if _, err := repo.SetCircuitRecord(context.Background(), 1); err != sql.ErrNoRows {
+		t.Errorf("SetCircuitRecord(1) error = %v, se esperaba sql.ErrNoRows", err)
+	}
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"f1_statshub_system/migrations"
+	"f1_statshub_system/models"
+	"f1_statshub_system/storage"
+	"f1_statshub_system/storage/storagetest"
+)
+
+// largeTables son las tablas que crecen con cada carrera ingerida: las consultas de los
+// handlers tienen que leerlas por índice, nunca recorrerlas enteras
+var largeTables = map[string]bool{
+	"laps":                true,
+	"positionhistory":     true,
+	"position":            true,
+	"fastestlap":          true,
+	"driversessionresult": true,
+	"driverseasonstats":   true,
+}
+
+// recordedQuery es una consulta que ejecutó un repositorio, con sus argumentos
+type recordedQuery struct {
+	query string
+	args  []driver.Value
+}
+
+// recorder guarda las consultas que pasan por las conexiones de recordingConnector
+type recorder struct {
+	mu      sync.Mutex
+	queries []recordedQuery
+}
+
+func (r *recorder) add(query string, args []driver.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, recordedQuery{query, args})
+}
+
+func (r *recorder) take() []recordedQuery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queries := r.queries
+	r.queries = nil
+	return queries
+}
+
+// recordingConnector abre conexiones del driver de SQLite que registran cada consulta de
+// lectura. Las conexiones no exponen QueryContext, así que database/sql prepara todas las
+// consultas y las ejecuta con Stmt.Query. Exec pasa directo al driver (las migraciones
+// tienen varias sentencias por Exec y los repositorios solo leen).
+type recordingConnector struct {
+	driver driver.Driver
+	dsn    string
+	rec    *recorder
+}
+
+func (c *recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingConn{Conn: conn, rec: c.rec}, nil
+}
+
+func (c *recordingConnector) Driver() driver.Driver { return c.driver }
+
+type recordingConn struct {
+	driver.Conn
+	rec *recorder
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingStmt{Stmt: stmt, query: query, rec: c.rec}, nil
+}
+
+type recordingStmt struct {
+	driver.Stmt
+	query string
+	rec   *recorder
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.rec.add(s.query, args)
+	return s.Stmt.Query(args)
+}
+
+// openRecording devuelve una base SQLite en memoria con los datos de storagetest.Seed
+// cuyas consultas quedan registradas en el recorder
+func openRecording(t *testing.T) (*storage.DB, *recorder) {
+	t.Helper()
+
+	// La base de storage solo se usa para obtener el driver y el Backend de SQLite
+	base, err := storage.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base.Close()
+
+	rec := &recorder{}
+	conn := sql.OpenDB(&recordingConnector{driver: base.Driver(), dsn: ":memory:", rec: rec})
+	conn.SetMaxOpenConns(1)
+	t.Cleanup(func() { conn.Close() })
+
+	db := &storage.DB{DB: conn, Backend: base.Backend}
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatal(err)
+	}
+	storagetest.Seed(t, db)
+	rec.take()
+	return db, rec
+}
+
+// tableAliases agrega a aliases los alias de tabla de query (y cada tabla con su propio
+// nombre), en minúsculas, para traducir las líneas de EXPLAIN QUERY PLAN que usan el alias
+func tableAliases(query string, aliases map[string]string) {
+	keywords := map[string]bool{"where": true, "on": true, "join": true, "left": true, "inner": true, "group": true, "order": true, "limit": true, "union": true}
+	from := regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+(\w+)(?:\s+(?:AS\s+)?(\w+))?`)
+	for _, m := range from.FindAllStringSubmatch(query, -1) {
+		table := strings.ToLower(m[1])
+		aliases[table] = table
+		if alias := strings.ToLower(m[2]); alias != "" && !keywords[alias] {
+			aliases[alias] = table
+		}
+	}
+}
+
+// viewDefinitions devuelve el SQL de las vistas de db, que SQLite expande en los planes
+func viewDefinitions(t *testing.T, db *storage.DB) []string {
+	t.Helper()
+	rows, err := db.Query(`SELECT sql FROM sqlite_master WHERE type = 'view'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var definitions []string
+	for rows.Next() {
+		var definition string
+		if err := rows.Scan(&definition); err != nil {
+			t.Fatal(err)
+		}
+		definitions = append(definitions, definition)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return definitions
+}
+
+// scannedTables devuelve las líneas del plan de q que recorren enteras tablas grandes
+func scannedTables(t *testing.T, db *storage.DB, views []string, q recordedQuery) []string {
+	t.Helper()
+
+	aliases := map[string]string{}
+	tableAliases(q.query, aliases)
+	for _, definition := range views {
+		tableAliases(definition, aliases)
+	}
+
+	args := make([]interface{}, len(q.args))
+	for i, arg := range q.args {
+		args[i] = arg
+	}
+	rows, err := db.Query("EXPLAIN QUERY PLAN "+q.query, args...)
+	if err != nil {
+		t.Fatalf("EXPLAIN QUERY PLAN: %v\n%s", err, q.query)
+	}
+	defer rows.Close()
+
+	// "SCAN l", "SCAN Laps USING INDEX ..." o, en versiones anteriores de SQLite,
+	// "SCAN TABLE Laps AS l"
+	scan := regexp.MustCompile(`^SCAN (?:TABLE )?(\w+)`)
+	var scanned []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			t.Fatal(err)
+		}
+		m := scan.FindStringSubmatch(detail)
+		if m == nil {
+			continue
+		}
+		table := strings.ToLower(m[1])
+		if resolved, ok := aliases[table]; ok {
+			table = resolved
+		}
+		if largeTables[table] {
+			scanned = append(scanned, detail)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return scanned
+}
+
+// TestQueryPlans ejecuta cada método de los repositorios, registra sus consultas y
+// falla si el plan de alguna recorre entera una tabla grande
+func TestQueryPlans(t *testing.T) {
+	db, rec := openRecording(t)
+	views := viewDefinitions(t, db)
+	rec.take()
+	ctx := context.Background()
+
+	drivers := NewDriverRepo(db)
+	sessions := NewSessionRepo(db)
+	results := NewResultRepo(db)
+	laps := NewLapRepo(db)
+	circuits := NewCircuitRepo(db)
+	sakhir := &models.Circuit{CircuitKey: 63, LapLengthKm: ptr(5.412)}
+
+	calls := map[string]func() error{
+		"DriverRepo.List": func() error {
+			_, _, err := drivers.List(ctx, DriverFilter{Team: "Ferrari", Country: "MON", OrderBy: "last_name ASC", Limit: 10})
+			return err
+		},
+		"DriverRepo.Exists": func() error { _, err := drivers.Exists(ctx, 1); return err },
+		"SessionRepo.List": func() error {
+			_, _, err := sessions.List(ctx, RaceFilter{Year: 2024, Country: "Bahrain", Circuit: "Sakhir", OrderBy: "date_start DESC", Limit: 10})
+			return err
+		},
+		"SessionRepo.Get":          func() error { _, err := sessions.Get(ctx, 9472); return err },
+		"ResultRepo.DriverSummary": func() error { _, err := results.DriverSummary(ctx, 1); return err },
+		"ResultRepo.DriverResults": func() error { _, err := results.DriverResults(ctx, 1); return err },
+		"ResultRepo.Podium":        func() error { _, err := results.Podium(ctx, 9472); return err },
+		"ResultRepo.LastPlace":     func() error { _, err := results.LastPlace(ctx, 9472); return err },
+		"ResultRepo.SeasonLeaders": func() error { _, err := results.SeasonLeaders(ctx, 2024, SeasonWins, 3); return err },
+		"LapRepo.FastestLap":       func() error { _, err := laps.FastestLap(ctx, 9472); return err },
+		"LapRepo.MaxSpeed":         func() error { _, err := laps.MaxSpeed(ctx, 9472); return err },
+		"LapRepo.LapsLed":          func() error { _, err := laps.LapsLed(ctx, 9472); return err },
+		"LapRepo.LeadChanges":      func() error { _, err := laps.LeadChanges(ctx, 9472); return err },
+		"LapRepo.Chart":            func() error { _, err := laps.Chart(ctx, 9472); return err },
+		"LapRepo.CircuitRecord":    func() error { _, err := laps.CircuitRecord(ctx, 9472); return err },
+		"LapRepo.SetCircuitRecord": func() error { _, err := laps.SetCircuitRecord(ctx, 9472); return err },
+		"CircuitRepo.List":         func() error { _, err := circuits.List(ctx); return err },
+		"CircuitRepo.Get":          func() error { _, err := circuits.Get(ctx, 63); return err },
+		"CircuitRepo.Races":        func() error { _, err := circuits.Races(ctx, sakhir); return err },
+		"CircuitRepo.LapRecord":    func() error { _, err := circuits.LapRecord(ctx, sakhir); return err },
+	}
+
+	// Cada método exportado de los repositorios tiene que estar en calls
+	for _, repo := range []interface{}{drivers, sessions, results, laps, circuits} {
+		typ := reflect.TypeOf(repo)
+		for i := 0; i < typ.NumMethod(); i++ {
+			name := typ.Elem().Name() + "." + typ.Method(i).Name
+			if _, ok := calls[name]; !ok {
+				t.Errorf("falta %s en TestQueryPlans", name)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(calls))
+	for name := range calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			if err := calls[name](); err != nil {
+				t.Fatal(err)
+			}
+			queries := rec.take()
+			if len(queries) == 0 {
+				t.Fatal("no se registró ninguna consulta")
+			}
+			for _, q := range queries {
+				if scanned := scannedTables(t, db, views, q); len(scanned) > 0 {
+					t.Errorf("la consulta recorre tablas grandes (%s):\n%s", strings.Join(scanned, "; "), q.query)
+				}
+			}
+			// Descartar los EXPLAIN, que también pasan por el recorder
+			rec.take()
+		})
+	}
+}
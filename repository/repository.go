@@ -0,0 +1,25 @@
+// Package repository reúne las consultas de lectura que usan los handlers de api.
+// Cada repositorio agrupa las consultas de una parte del modelo (pilotos, carreras,
+// resultados, vueltas y circuitos) y devuelve los tipos de models, de modo que los
+// handlers solo validan la solicitud, llaman al repositorio y arman la respuesta.
+//
+// Todas las consultas reciben el contexto de la solicitud para cancelarse si el cliente
+// se desconecta, y funcionan con cualquiera de los motores de storage.
+package repository
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrNotFound indica que el registro pedido no existe
+var ErrNotFound = errors.New("registro no encontrado")
+
+// nullFloatToPtr devuelve nil cuando el valor es NULL, para que la API responda null
+// en vez de un 0 que podría confundirse con un dato real
+func nullFloatToPtr(n sql.NullFloat64) *float64 {
+	if n.Valid {
+		return &n.Float64
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"math"
+	"testing"
+
+	"f1_statshub_system/storage"
+	"f1_statshub_system/storage/storagetest"
+)
+
+// Los tests del paquete usan los datos de storagetest.Seed (ver ese paquete para el
+// detalle de cada carrera)
+
+func seeded(t *testing.T) *storage.DB {
+	t.Helper()
+	return storagetest.OpenSeeded(t)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// approx compara valores calculados por la base, que pueden diferir en los últimos
+// decimales entre motores
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func approxPtr(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return approx(*a, *b)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"f1_statshub_system/models"
+	"f1_statshub_system/storage"
+)
+
+// ResultRepo consulta las posiciones finales y las estadísticas precalculadas
+// (Position, DriverSessionResult y DriverSeasonStats)
+type ResultRepo struct {
+	db *storage.DB
+}
+
+func NewResultRepo(db *storage.DB) *ResultRepo {
+	return &ResultRepo{db: db}
+}
+
+// DriverSummary suma las estadísticas de todas las temporadas de un piloto
+func (r *ResultRepo) DriverSummary(ctx context.Context, driverNumber int) (models.PerformanceSummary, error) {
+	var summary models.PerformanceSummary
+	var maxSpeed sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(SUM(wins), 0),
+			COALESCE(SUM(podiums), 0),
+			MAX(max_speed),
+			COALESCE(SUM(laps_led), 0)
+		FROM DriverSeasonStats
+		WHERE driver_number = ?
+	`, driverNumber).Scan(&summary.Wins, &summary.Top3Finishes, &maxSpeed, &summary.LapsLed)
+	summary.MaxSpeed = nullFloatToPtr(maxSpeed)
+	return summary, err
+}
+
+// DriverResults devuelve los resultados de un piloto en las carreras en que tiene
+// posición final, en orden cronológico. Race queda vacío: la etiqueta depende del idioma.
+func (r *ResultRepo) DriverResults(ctx context.Context, driverNumber int) ([]models.DriverRaceResult, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			r.session_key,
+			s.circuit_short_name,
+			s.country_name,
+			r.position,
+			r.best_lap_duration,
+			r.max_speed,
+			r.fastest_lap,
+			r.laps_led
+		FROM DriverSessionResult r
+		JOIN Session s ON s.session_key = r.session_key
+		WHERE r.driver_number = ? AND r.position IS NOT NULL
+		ORDER BY s.date_start ASC
+	`, driverNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.DriverRaceResult
+	for rows.Next() {
+		var result models.DriverRaceResult
+		var bestLap, maxSpeed sql.NullFloat64
+		if err := rows.Scan(&result.SessionKey, &result.CircuitShortName, &result.CountryName, &result.Position, &bestLap, &maxSpeed, &result.FastestLap, &result.LapsLed); err != nil {
+			return nil, err
+		}
+		result.MaxSpeed = nullFloatToPtr(maxSpeed)
+		result.BestLapDuration = nullFloatToPtr(bestLap)
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// Podium devuelve los tres primeros de una carrera
+func (r *ResultRepo) Podium(ctx context.Context, sessionKey int) ([]models.RaceResult, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT p.position, d.first_name || ' ' || d.last_name, d.team_name, d.country_code
+		FROM Position p
+		JOIN Driver d ON d.driver_number = p.driver_number
+		WHERE p.session_key = ?
+		ORDER BY p.position ASC
+		LIMIT 3
+	`, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var podium []models.RaceResult
+	for rows.Next() {
+		var result models.RaceResult
+		if err := rows.Scan(&result.Position.Number, &result.Driver, &result.Team, &result.Country); err != nil {
+			return nil, err
+		}
+		podium = append(podium, result)
+	}
+	return podium, rows.Err()
+}
+
+// LastPlace devuelve el último clasificado de una carrera. Si la carrera no tiene
+// posiciones devuelve un resultado vacío sin error.
+func (r *ResultRepo) LastPlace(ctx context.Context, sessionKey int) (models.RaceResult, error) {
+	var result models.RaceResult
+	err := r.db.QueryRowContext(ctx, `
+		SELECT p.position, d.first_name || ' ' || d.last_name, d.team_name, d.country_code
+		FROM Position p
+		JOIN Driver d ON d.driver_number = p.driver_number
+		WHERE p.session_key = ?
+		ORDER BY p.position DESC
+		LIMIT 1
+	`, sessionKey).Scan(&result.Position.Number, &result.Driver, &result.Team, &result.Country)
+	if err == sql.ErrNoRows {
+		return models.RaceResult{}, nil
+	}
+	return result, err
+}
+
+// SeasonStat es una columna de DriverSeasonStats por la que se puede rankear
+type SeasonStat string
+
+const (
+	SeasonWins        SeasonStat = "wins"
+	SeasonPodiums     SeasonStat = "podiums"
+	SeasonFastestLaps SeasonStat = "fastest_laps"
+	SeasonLapsLed     SeasonStat = "laps_led"
+)
+
+// SeasonLeader es un piloto del ranking de una temporada con su valor en la estadística
+type SeasonLeader struct {
+	models.RankedDriver
+	Value int
+}
+
+// SeasonLeaders devuelve los limit pilotos con más stat en la temporada year, sin los
+// que tienen 0. Los empates se ordenan por número de piloto.
+func (r *ResultRepo) SeasonLeaders(ctx context.Context, year int, stat SeasonStat, limit int) ([]SeasonLeader, error) {
+	switch stat {
+	case SeasonWins, SeasonPodiums, SeasonFastestLaps, SeasonLapsLed:
+	default:
+		return nil, fmt.Errorf("estadística de temporada desconocida: %s", stat)
+	}
+
+	column := "st." + string(stat)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			d.first_name || ' ' || d.last_name AS driver,
+			d.team_name,
+			d.country_code,
+			`+column+`
+		FROM DriverSeasonStats st
+		JOIN Driver d ON d.driver_number = st.driver_number
+		WHERE st.year = ? AND `+column+` > 0
+		ORDER BY `+column+` DESC, st.driver_number ASC
+		LIMIT ?
+	`, year, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaders []SeasonLeader
+	for rows.Next() {
+		leader := SeasonLeader{RankedDriver: models.RankedDriver{Position: len(leaders) + 1}}
+		if err := rows.Scan(&leader.Driver, &leader.Team, &leader.Country, &leader.Value); err != nil {
+			return nil, err
+		}
+		leaders = append(leaders, leader)
+	}
+	return leaders, rows.Err()
+}
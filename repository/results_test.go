@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"f1_statshub_system/models"
+	"f1_statshub_system/stats"
+)
+
+func TestResultRepoDriverSummary(t *testing.T) {
+	repo := NewResultRepo(seeded(t))
+
+	tests := []struct {
+		driverNumber int
+		want         models.PerformanceSummary
+	}{
+		{1, models.PerformanceSummary{Wins: 2, Top3Finishes: 3, MaxSpeed: ptr(323.6), LapsLed: 6}},
+		{16, models.PerformanceSummary{Wins: 1, Top3Finishes: 3, MaxSpeed: ptr(322.0), LapsLed: 2}},
+		{4, models.PerformanceSummary{Wins: 0, Top3Finishes: 3, MaxSpeed: ptr(321.1), LapsLed: 0}},
+		{99, models.PerformanceSummary{}},
+	}
+	for _, tc := range tests {
+		summary, err := repo.DriverSummary(context.Background(), tc.driverNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(summary, tc.want) {
+			t.Errorf("DriverSummary(%d) = %+v, se esperaba %+v", tc.driverNumber, summary, tc.want)
+		}
+	}
+}
+
+// TestResultRepoWithoutSpeeds comprueba que un piloto sin velocidades ni tiempos de vuelta
+// informados tenga max_speed y best_lap_duration nil en lugar de 0
+func TestResultRepoWithoutSpeeds(t *testing.T) {
+	db := seeded(t)
+	if _, err := db.Exec(`UPDATE Laps SET lap_duration = NULL, st_speed = NULL WHERE driver_number = 4`); err != nil {
+		t.Fatal(err)
+	}
+	if err := stats.Rebuild(db); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewResultRepo(db)
+
+	summary, err := repo.DriverSummary(context.Background(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.MaxSpeed != nil {
+		t.Errorf("DriverSummary(4).MaxSpeed = %v, se esperaba nil", *summary.MaxSpeed)
+	}
+
+	results, err := repo.DriverResults(context.Background(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("DriverResults(4) devolvió %d carreras, se esperaban 3", len(results))
+	}
+	for _, r := range results {
+		if r.MaxSpeed != nil || r.BestLapDuration != nil {
+			t.Errorf("carrera %d: MaxSpeed %v y BestLapDuration %v, se esperaba nil", r.SessionKey, r.MaxSpeed, r.BestLapDuration)
+		}
+	}
+}
+
+func TestResultRepoDriverResults(t *testing.T) {
+	repo := NewResultRepo(seeded(t))
+
+	tests := []struct {
+		driverNumber int
+		want         []models.DriverRaceResult
+	}{
+		{1, []models.DriverRaceResult{
+			{SessionKey: 7953, CircuitShortName: "Sakhir", CountryName: "Bahrain", Position: 1, FastestLap: true, MaxSpeed: ptr(309.5), BestLapDuration: ptr(95.2), LapsLed: 2},
+			{SessionKey: 9472, CircuitShortName: "Sakhir", CountryName: "Bahrain", Position: 1, FastestLap: true, MaxSpeed: ptr(312.4), BestLapDuration: ptr(94.5), LapsLed: 3},
+			{SessionKey: 9480, CircuitShortName: "Jeddah", CountryName: "Saudi Arabia", Position: 2, FastestLap: true, MaxSpeed: ptr(323.6), BestLapDuration: ptr(89.9), LapsLed: 1},
+		}},
+		// La vuelta 3 de Norris en 9472 no tiene tiempo ni velocidad y no cuenta
+		{4, []models.DriverRaceResult{
+			{SessionKey: 7953, CircuitShortName: "Sakhir", CountryName: "Bahrain", Position: 3, MaxSpeed: ptr(307.3), BestLapDuration: ptr(95.9)},
+			{SessionKey: 9472, CircuitShortName: "Sakhir", CountryName: "Bahrain", Position: 2, MaxSpeed: ptr(311.0), BestLapDuration: ptr(94.8)},
+			{SessionKey: 9480, CircuitShortName: "Jeddah", CountryName: "Saudi Arabia", Position: 3, MaxSpeed: ptr(321.1), BestLapDuration: ptr(90.8)},
+		}},
+		{99, nil},
+	}
+	for _, tc := range tests {
+		results, err := repo.DriverResults(context.Background(), tc.driverNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(results, tc.want) {
+			t.Errorf("DriverResults(%d) = %+v, se esperaba %+v", tc.driverNumber, results, tc.want)
+		}
+	}
+}
+
+func TestResultRepoPodiumAndLastPlace(t *testing.T) {
+	repo := NewResultRepo(seeded(t))
+
+	verstappen := func(position int) models.RaceResult {
+		return models.RaceResult{Position: models.ResultPosition{Number: position}, Driver: "Max Verstappen", Team: "Red Bull Racing", Country: ptr("NED")}
+	}
+	leclerc := func(position int) models.RaceResult {
+		return models.RaceResult{Position: models.ResultPosition{Number: position}, Driver: "Charles Leclerc", Team: "Ferrari", Country: ptr("MON")}
+	}
+	norris := func(position int) models.RaceResult {
+		return models.RaceResult{Position: models.ResultPosition{Number: position}, Driver: "Lando Norris", Team: "McLaren", Country: ptr("GBR")}
+	}
+
+	tests := []struct {
+		sessionKey int
+		podium     []models.RaceResult
+		lastPlace  models.RaceResult
+	}{
+		{9472, []models.RaceResult{verstappen(1), norris(2), leclerc(3)}, leclerc(3)},
+		{9480, []models.RaceResult{leclerc(1), verstappen(2), norris(3)}, norris(3)},
+		{1, nil, models.RaceResult{}},
+	}
+	for _, tc := range tests {
+		podium, err := repo.Podium(context.Background(), tc.sessionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(podium, tc.podium) {
+			t.Errorf("Podium(%d) = %+v, se esperaba %+v", tc.sessionKey, podium, tc.podium)
+		}
+
+		last, err := repo.LastPlace(context.Background(), tc.sessionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(last, tc.lastPlace) {
+			t.Errorf("LastPlace(%d) = %+v, se esperaba %+v", tc.sessionKey, last, tc.lastPlace)
+		}
+	}
+}
+
+func TestResultRepoSeasonLeaders(t *testing.T) {
+	repo := NewResultRepo(seeded(t))
+
+	leader := func(position int, driver string, value int) SeasonLeader {
+		teams := map[string][2]string{
+			"Max Verstappen":  {"Red Bull Racing", "NED"},
+			"Charles Leclerc": {"Ferrari", "MON"},
+			"Lando Norris":    {"McLaren", "GBR"},
+		}
+		return SeasonLeader{
+			RankedDriver: models.RankedDriver{Position: position, Driver: driver, Team: teams[driver][0], Country: ptr(teams[driver][1])},
+			Value:        value,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		year  int
+		stat  SeasonStat
+		limit int
+		want  []SeasonLeader
+	}{
+		// Los empates se ordenan por número de piloto y los que tienen 0 no aparecen
+		{"victorias", 2024, SeasonWins, 3, []SeasonLeader{leader(1, "Max Verstappen", 1), leader(2, "Charles Leclerc", 1)}},
+		{"podios", 2024, SeasonPodiums, 3, []SeasonLeader{leader(1, "Max Verstappen", 2), leader(2, "Lando Norris", 2), leader(3, "Charles Leclerc", 2)}},
+		{"vueltas rápidas", 2024, SeasonFastestLaps, 3, []SeasonLeader{leader(1, "Max Verstappen", 2)}},
+		{"vueltas lideradas", 2024, SeasonLapsLed, 3, []SeasonLeader{leader(1, "Max Verstappen", 4), leader(2, "Charles Leclerc", 2)}},
+		{"límite", 2024, SeasonPodiums, 1, []SeasonLeader{leader(1, "Max Verstappen", 2)}},
+		{"otra temporada", 2023, SeasonLapsLed, 3, []SeasonLeader{leader(1, "Max Verstappen", 2)}},
+		{"temporada sin datos", 1990, SeasonWins, 3, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			leaders, err := repo.SeasonLeaders(context.Background(), tc.year, tc.stat, tc.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(leaders, tc.want) {
+				t.Errorf("SeasonLeaders = %+v, se esperaba %+v", leaders, tc.want)
+			}
+		})
+	}
+
+	if _, err := repo.SeasonLeaders(context.Background(), 2024, SeasonStat("races; DROP TABLE Driver"), 3); err == nil {
+		t.Error("SeasonLeaders aceptó una estadística desconocida")
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"f1_statshub_system/models"
+	"f1_statshub_system/storage"
+)
+
+// SessionRepo consulta las carreras (tabla Session)
+type SessionRepo struct {
+	db *storage.DB
+}
+
+func NewSessionRepo(db *storage.DB) *SessionRepo {
+	return &SessionRepo{db: db}
+}
+
+// RaceFilter filtra y pagina el listado de carreras. Year 0 y los textos vacíos no
+// filtran. OrderBy tiene las mismas restricciones que en DriverFilter.
+type RaceFilter struct {
+	Year    int
+	Country string
+	Circuit string
+	OrderBy string
+	Limit   int
+	Offset  int
+}
+
+// List devuelve la página de carreras pedida y el total que cumple los filtros
+func (r *SessionRepo) List(ctx context.Context, f RaceFilter) ([]models.Race, int, error) {
+	filter := `
+		FROM Session
+		WHERE session_name = 'Race'
+		  AND (? = 0 OR year = ?)
+		  AND (? = '' OR LOWER(country_name) = LOWER(?))
+		  AND (? = '' OR LOWER(circuit_short_name) = LOWER(?))
+	`
+	filterArgs := []interface{}{f.Year, f.Year, f.Country, f.Country, f.Circuit, f.Circuit}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) "+filter, filterArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT session_key, country_name, date_start, year, circuit_short_name
+	`+filter+`
+		ORDER BY `+f.OrderBy+`, date_start ASC
+		LIMIT ? OFFSET ?
+	`, append(filterArgs, f.Limit, f.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	races := []models.Race{}
+	for rows.Next() {
+		var race models.Race
+		if err := rows.Scan(&race.SessionKey, &race.CountryName, &race.DateStart, &race.Year, &race.CircuitShortName); err != nil {
+			return nil, 0, err
+		}
+		races = append(races, race)
+	}
+	return races, total, rows.Err()
+}
+
+// Get devuelve una sesión por su session_key, o ErrNotFound si no existe
+func (r *SessionRepo) Get(ctx context.Context, sessionKey int) (*models.Race, error) {
+	race := models.Race{SessionKey: sessionKey}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT country_name, date_start, year, circuit_short_name
+		FROM Session WHERE session_key = ?
+	`, sessionKey).Scan(&race.CountryName, &race.DateStart, &race.Year, &race.CircuitShortName)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &race, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"f1_statshub_system/models"
+)
+
+func TestSessionRepoList(t *testing.T) {
+	repo := NewSessionRepo(seeded(t))
+
+	tests := []struct {
+		name   string
+		filter RaceFilter
+		keys   []int
+		total  int
+	}{
+		{"todas", RaceFilter{OrderBy: "date_start ASC", Limit: 100}, []int{7953, 9472, 9480}, 3},
+		{"temporada", RaceFilter{Year: 2024, OrderBy: "date_start ASC", Limit: 100}, []int{9472, 9480}, 2},
+		{"país sin distinguir mayúsculas", RaceFilter{Country: "bahrain", OrderBy: "date_start ASC", Limit: 100}, []int{7953, 9472}, 2},
+		{"circuito", RaceFilter{Circuit: "JEDDAH", OrderBy: "date_start ASC", Limit: 100}, []int{9480}, 1},
+		{"orden descendente paginado", RaceFilter{OrderBy: "date_start DESC", Limit: 2}, []int{9480, 9472}, 3},
+		{"desempate por fecha", RaceFilter{OrderBy: "circuit_short_name DESC", Limit: 100}, []int{7953, 9472, 9480}, 3},
+		{"sin resultados", RaceFilter{Year: 2022, OrderBy: "date_start ASC", Limit: 100}, []int{}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			races, total, err := repo.List(context.Background(), tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys := []int{}
+			for _, r := range races {
+				keys = append(keys, r.SessionKey)
+			}
+			if !reflect.DeepEqual(keys, tc.keys) || total != tc.total {
+				t.Errorf("List = %v (total %d), se esperaba %v (total %d)", keys, total, tc.keys, tc.total)
+			}
+		})
+	}
+}
+
+func TestSessionRepoGet(t *testing.T) {
+	repo := NewSessionRepo(seeded(t))
+
+	tests := []struct {
+		sessionKey int
+		want       *models.Race
+		err        error
+	}{
+		{9472, &models.Race{SessionKey: 9472, CountryName: "Bahrain", DateStart: "2024-03-02T15:00:00+00:00", Year: 2024, CircuitShortName: "Sakhir"}, nil},
+		{9480, &models.Race{SessionKey: 9480, CountryName: "Saudi Arabia", DateStart: "2024-03-09T17:00:00+00:00", Year: 2024, CircuitShortName: "Jeddah"}, nil},
+		{1, nil, ErrNotFound},
+	}
+	for _, tc := range tests {
+		race, err := repo.Get(context.Background(), tc.sessionKey)
+		if err != tc.err {
+			t.Fatalf("Get(%d) error = %v, se esperaba %v", tc.sessionKey, err, tc.err)
+		}
+		if !reflect.DeepEqual(race, tc.want) {
+			t.Errorf("Get(%d) = %+v, se esperaba %+v", tc.sessionKey, race, tc.want)
+		}
+	}
+}
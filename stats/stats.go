@@ -0,0 +1,194 @@
+// Package stats reconstruye las tablas de estadísticas precalculadas (FastestLap,
+// CircuitLapRecord, DriverSessionResult y DriverSeasonStats) a partir de los datos
+// ingeridos, para que los handlers las lean con una consulta por índice en vez de
+// recalcularlas en cada solicitud.
+package stats
+
+import (
+	"fmt"
+
+	"f1_statshub_system/storage"
+)
+
+// rebuildFastestLaps guarda la vuelta más rápida de cada carrera:
+//   - Cuentan las vueltas con lap_duration informado por OpenF1, aunque les falte algún
+//     sector. Las vueltas cuyo tiempo es la suma de los tres sectores
+//     (lap_duration_estimated = 1) no cuentan, ni tampoco para los récords por circuito.
+//     Las vueltas sin lap_duration (NULL) tampoco: MIN las ignora y la comparación con
+//     NULL nunca es verdadera.
+//   - Si hay empate en el mejor tiempo se guarda una fila por cada vuelta empatada.
+const rebuildFastestLaps = `
+	WITH best AS (
+		SELECT session_key, MIN(lap_duration) AS min_time
+		FROM Laps
+		WHERE lap_duration_estimated = 0
+		GROUP BY session_key
+	)
+	INSERT INTO FastestLap
+	(session_key, driver_number, lap_number, lap_duration,
+	 duration_sector_1, duration_sector_2, duration_sector_3, date_start)
+	SELECT
+		l.session_key,
+		l.driver_number,
+		l.lap_number,
+		l.lap_duration,
+		l.duration_sector_1,
+		l.duration_sector_2,
+		l.duration_sector_3,
+		l.date_start
+	FROM Laps l
+	JOIN best b ON b.session_key = l.session_key AND b.min_time = l.lap_duration
+	WHERE l.lap_duration_estimated = 0`
+
+// upsertCircuitRecords toma la vuelta rápida de cada circuito entre todas las carreras
+// ingeridas usando FastestLap, para aplicar el mismo criterio de vueltas válidas (en
+// empate gana la más antigua; las de fecha desconocida quedan últimas), y solo reemplaza
+// el récord guardado si es mejor. CircuitLapRecord no se vacía: un récord de una carrera
+// que ya no está en Laps se conserva hasta que otra vuelta lo supere.
+const upsertCircuitRecords = `
+	INSERT INTO CircuitLapRecord (circuit_key, driver_number, session_key, lap_number, lap_duration)
+	SELECT circuit_key, driver_number, session_key, lap_number, lap_duration
+	FROM (
+		SELECT
+			c.circuit_key,
+			l.driver_number,
+			l.session_key,
+			l.lap_number,
+			l.lap_duration,
+			ROW_NUMBER() OVER (
+				PARTITION BY c.circuit_key
+				ORDER BY l.lap_duration ASC, l.date_start IS NULL, l.date_start ASC
+			) AS lap_rank
+		FROM FastestLap l
+		JOIN Session s ON s.session_key = l.session_key
+		JOIN Circuit c ON c.circuit_key = s.circuit_key
+		WHERE s.session_name = 'Race'
+	) ranked
+	WHERE lap_rank = 1
+	ON CONFLICT (circuit_key) DO UPDATE SET
+		driver_number = excluded.driver_number,
+		session_key = excluded.session_key,
+		lap_number = excluded.lap_number,
+		lap_duration = excluded.lap_duration
+	WHERE excluded.lap_duration < CircuitLapRecord.lap_duration`
+
+// rebuildSessionResults tiene una fila por piloto y carrera en la que tiene posición
+// o vueltas registradas. position es la de Position, que la ingesta deja con el último
+// registro de OpenF1 del piloto en la carrera (su posición final), y es NULL si solo hay
+// vueltas. best_lap_duration y max_speed son NULL si ninguna vuelta del piloto tiene el
+// dato (MIN y MAX ignoran NULL); como en FastestLap, best_lap_duration no toma las
+// vueltas con lap_duration estimado.
+const rebuildSessionResults = `
+	INSERT INTO DriverSessionResult (driver_number, session_key, year, position, best_lap_duration, max_speed, fastest_lap, laps_led)
+	SELECT
+		ds.driver_number,
+		ds.session_key,
+		s.year,
+		(
+			SELECT p.position
+			FROM Position p
+			WHERE p.driver_number = ds.driver_number
+			AND p.session_key = ds.session_key
+		) AS position,
+		(
+			SELECT MIN(l.lap_duration)
+			FROM Laps l
+			WHERE l.driver_number = ds.driver_number
+			AND l.session_key = ds.session_key
+			AND l.lap_duration_estimated = 0
+		) AS best_lap_duration,
+		(
+			SELECT MAX(l.st_speed)
+			FROM Laps l
+			WHERE l.driver_number = ds.driver_number
+			AND l.session_key = ds.session_key
+		) AS max_speed,
+		CASE WHEN EXISTS (
+			SELECT 1
+			FROM FastestLap fl
+			WHERE fl.driver_number = ds.driver_number
+			AND fl.session_key = ds.session_key
+		) THEN 1 ELSE 0 END AS fastest_lap,
+		(
+			SELECT COUNT(*)
+			FROM LapPosition lp
+			WHERE lp.driver_number = ds.driver_number
+			AND lp.session_key = ds.session_key
+			AND lp.position = 1
+		) AS laps_led
+	FROM (
+		SELECT driver_number, session_key FROM Position
+		UNION
+		SELECT DISTINCT driver_number, session_key FROM Laps
+	) ds
+	JOIN Session s ON s.session_key = ds.session_key`
+
+// rebuildSeasonStats agrega DriverSessionResult por piloto y temporada
+const rebuildSeasonStats = `
+	INSERT INTO DriverSeasonStats (driver_number, year, races, wins, podiums, fastest_laps, laps_led, max_speed)
+	SELECT
+		driver_number,
+		year,
+		COUNT(position),
+		COUNT(CASE WHEN position = 1 THEN 1 END),
+		COUNT(CASE WHEN position <= 3 THEN 1 END),
+		SUM(fastest_lap),
+		SUM(laps_led),
+		MAX(max_speed)
+	FROM DriverSessionResult
+	GROUP BY driver_number, year`
+
+// RebuildFastestLaps vuelve a calcular FastestLap dentro de tx
+func RebuildFastestLaps(tx *storage.Tx) error {
+	if _, err := tx.Exec("DELETE FROM FastestLap"); err != nil {
+		return fmt.Errorf("error al vaciar FastestLap: %v", err)
+	}
+	if _, err := tx.Exec(rebuildFastestLaps); err != nil {
+		return fmt.Errorf("error al calcular FastestLap: %v", err)
+	}
+	return nil
+}
+
+// RebuildCircuitRecords actualiza CircuitLapRecord con las vueltas de FastestLap dentro
+// de tx, así que va después de RebuildFastestLaps
+func RebuildCircuitRecords(tx *storage.Tx) error {
+	if _, err := tx.Exec(upsertCircuitRecords); err != nil {
+		return fmt.Errorf("error al actualizar CircuitLapRecord: %v", err)
+	}
+	return nil
+}
+
+// Rebuild vuelve a calcular las tablas de estadísticas en una sola transacción, de modo
+// que los handlers nunca vean las tablas a medio llenar. DriverSessionResult usa
+// FastestLap, por eso las vueltas rápidas se calculan primero.
+func Rebuild(db *storage.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := RebuildFastestLaps(tx); err != nil {
+		return err
+	}
+	if err := RebuildCircuitRecords(tx); err != nil {
+		return err
+	}
+
+	steps := []struct {
+		name  string
+		query string
+	}{
+		{"vaciar DriverSeasonStats", "DELETE FROM DriverSeasonStats"},
+		{"vaciar DriverSessionResult", "DELETE FROM DriverSessionResult"},
+		{"calcular DriverSessionResult", rebuildSessionResults},
+		{"calcular DriverSeasonStats", rebuildSeasonStats},
+	}
+	for _, step := range steps {
+		if _, err := tx.Exec(step.query); err != nil {
+			return fmt.Errorf("error al %s: %v", step.name, err)
+		}
+	}
+
+	return tx.Commit()
+}
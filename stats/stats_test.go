@@ -0,0 +1,290 @@
+package stats_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"f1_statshub_system/stats"
+	"f1_statshub_system/storage"
+	"f1_statshub_system/storage/storagetest"
+)
+
+// lap identifica una vuelta guardada en FastestLap o CircuitLapRecord
+type lap struct {
+	SessionKey   int
+	DriverNumber int
+	LapNumber    int
+	LapDuration  float64
+}
+
+// record es una fila de CircuitLapRecord
+type record struct {
+	CircuitKey int
+	lap
+}
+
+// exec ejecuta las sentencias en db y falla el test si alguna da error
+func exec(t *testing.T, db *storage.DB, statements ...string) {
+	t.Helper()
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%v\n%s", err, statement)
+		}
+	}
+}
+
+// rebuild llama a stats.Rebuild y falla el test si da error
+func rebuild(t *testing.T, db *storage.DB) {
+	t.Helper()
+	if err := stats.Rebuild(db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fastestLaps lee FastestLap ordenada por carrera y piloto
+func fastestLaps(t *testing.T, db *storage.DB) []lap {
+	t.Helper()
+	rows, err := db.Query(`SELECT session_key, driver_number, lap_number, lap_duration
+		FROM FastestLap ORDER BY session_key, driver_number, lap_number`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var laps []lap
+	for rows.Next() {
+		var l lap
+		if err := rows.Scan(&l.SessionKey, &l.DriverNumber, &l.LapNumber, &l.LapDuration); err != nil {
+			t.Fatal(err)
+		}
+		laps = append(laps, l)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return laps
+}
+
+// circuitRecords lee CircuitLapRecord ordenada por circuito
+func circuitRecords(t *testing.T, db *storage.DB) []record {
+	t.Helper()
+	rows, err := db.Query(`SELECT circuit_key, session_key, driver_number, lap_number, lap_duration
+		FROM CircuitLapRecord ORDER BY circuit_key`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var records []record
+	for rows.Next() {
+		var r record
+		if err := rows.Scan(&r.CircuitKey, &r.SessionKey, &r.DriverNumber, &r.LapNumber, &r.LapDuration); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestRebuildFastestLaps(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup []string
+		want  []lap
+	}{
+		{
+			// La vuelta 2 de Leclerc en 9472 (94.3) es estimada y la vuelta 3 de Norris no
+			// tiene lap_duration
+			name: "seed",
+			want: []lap{
+				{7953, 1, 2, 95.2},
+				{9472, 1, 3, 94.5},
+				{9480, 1, 2, 89.9},
+			},
+		},
+		{
+			name: "empate",
+			setup: []string{
+				`INSERT INTO Laps (driver_number, session_key, lap_number, lap_duration, lap_duration_estimated, date_start)
+				VALUES (16, 7953, 3, 95.2, 0, '2023-03-05T15:03:12.100000+00:00')`,
+			},
+			want: []lap{
+				{7953, 1, 2, 95.2},
+				{7953, 16, 3, 95.2},
+				{9472, 1, 3, 94.5},
+				{9480, 1, 2, 89.9},
+			},
+		},
+		{
+			name: "empate con una vuelta estimada",
+			setup: []string{
+				`INSERT INTO Laps (driver_number, session_key, lap_number, lap_duration, lap_duration_estimated, date_start)
+				VALUES (4, 9472, 4, 94.5, 1, '2024-03-02T15:04:47.000000+00:00')`,
+			},
+			want: []lap{
+				{7953, 1, 2, 95.2},
+				{9472, 1, 3, 94.5},
+				{9480, 1, 2, 89.9},
+			},
+		},
+		{
+			// Una carrera sin ninguna vuelta válida no tiene vuelta rápida
+			name: "solo vueltas estimadas o sin lap_duration",
+			setup: []string{
+				`UPDATE Laps SET lap_duration_estimated = 1 WHERE session_key = 9480 AND driver_number <> 4`,
+				`UPDATE Laps SET lap_duration = NULL WHERE session_key = 9480 AND driver_number = 4`,
+			},
+			want: []lap{
+				{7953, 1, 2, 95.2},
+				{9472, 1, 3, 94.5},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db := storagetest.OpenSeeded(t)
+			exec(t, db, tc.setup...)
+			rebuild(t, db)
+
+			if got := fastestLaps(t, db); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FastestLap = %+v, se esperaba %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRebuildCircuitRecords(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup []string
+		want  []record
+	}{
+		{
+			// El 94.3 estimado de Leclerc en 9472 no es récord de Sakhir
+			name: "seed",
+			want: []record{
+				{63, lap{9472, 1, 3, 94.5}},
+				{149, lap{9480, 1, 2, 89.9}},
+			},
+		},
+		{
+			name: "una vuelta más rápida reemplaza el récord",
+			setup: []string{
+				`INSERT INTO Laps (driver_number, session_key, lap_number, lap_duration, lap_duration_estimated, date_start)
+				VALUES (4, 9472, 4, 93.9, 0, '2024-03-02T15:04:47.000000+00:00')`,
+			},
+			want: []record{
+				{63, lap{9472, 4, 4, 93.9}},
+				{149, lap{9480, 1, 2, 89.9}},
+			},
+		},
+		{
+			// Sin las vueltas de 9472 la vuelta rápida de Sakhir es el 95.2 de 7953, más
+			// lento que el récord guardado
+			name: "se conserva el récord anterior más rápido",
+			setup: []string{
+				`DELETE FROM Laps WHERE session_key = 9472`,
+			},
+			want: []record{
+				{63, lap{9472, 1, 3, 94.5}},
+				{149, lap{9480, 1, 2, 89.9}},
+			},
+		},
+		{
+			name: "un empate no reemplaza el récord",
+			setup: []string{
+				`INSERT INTO Laps (driver_number, session_key, lap_number, lap_duration, lap_duration_estimated, date_start)
+				VALUES (16, 7953, 3, 94.5, 0, '2023-03-05T15:03:12.100000+00:00')`,
+			},
+			want: []record{
+				{63, lap{9472, 1, 3, 94.5}},
+				{149, lap{9480, 1, 2, 89.9}},
+			},
+		},
+		{
+			// Sin récord guardado, en empate gana la vuelta más antigua
+			name: "empate sin récord guardado",
+			setup: []string{
+				`DELETE FROM CircuitLapRecord`,
+				`INSERT INTO Laps (driver_number, session_key, lap_number, lap_duration, lap_duration_estimated, date_start)
+				VALUES (16, 7953, 3, 94.5, 0, '2023-03-05T15:03:12.100000+00:00')`,
+			},
+			want: []record{
+				{63, lap{7953, 16, 3, 94.5}},
+				{149, lap{9480, 1, 2, 89.9}},
+			},
+		},
+		{
+			name: "una vuelta estimada más rápida no es récord",
+			setup: []string{
+				`INSERT INTO Laps (driver_number, session_key, lap_number, lap_duration, lap_duration_estimated, date_start)
+				VALUES (4, 9480, 4, 88.0, 1, '2024-03-09T17:04:34.300000+00:00')`,
+			},
+			want: []record{
+				{63, lap{9472, 1, 3, 94.5}},
+				{149, lap{9480, 1, 2, 89.9}},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db := storagetest.OpenSeeded(t)
+			exec(t, db, tc.setup...)
+			rebuild(t, db)
+
+			if got := circuitRecords(t, db); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("CircuitLapRecord = %+v, se esperaba %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+// bestLap lee best_lap_duration de DriverSessionResult
+func bestLap(t *testing.T, db *storage.DB, driverNumber, sessionKey int) sql.NullFloat64 {
+	t.Helper()
+	var best sql.NullFloat64
+	err := db.QueryRow(db.Rebind(`SELECT best_lap_duration FROM DriverSessionResult
+		WHERE driver_number = ? AND session_key = ?`), driverNumber, sessionKey).Scan(&best)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return best
+}
+
+func TestRebuildBestLapDuration(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        []string
+		driverNumber int
+		want         sql.NullFloat64
+	}{
+		// La vuelta 2 de Leclerc en 9472 (94.3) es estimada: cuenta la vuelta 3
+		{"vuelta estimada", nil, 16, sql.NullFloat64{Float64: 95.1, Valid: true}},
+		// La vuelta 3 de Norris en 9472 no tiene lap_duration
+		{"vuelta sin lap_duration", nil, 4, sql.NullFloat64{Float64: 94.8, Valid: true}},
+		{
+			"sin vueltas válidas",
+			[]string{
+				`UPDATE Laps SET lap_duration_estimated = 1 WHERE driver_number = 16 AND session_key = 9472 AND lap_number <> 1`,
+				`UPDATE Laps SET lap_duration = NULL WHERE driver_number = 16 AND session_key = 9472 AND lap_number = 1`,
+			},
+			16,
+			sql.NullFloat64{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db := storagetest.OpenSeeded(t)
+			exec(t, db, tc.setup...)
+			rebuild(t, db)
+
+			if got := bestLap(t, db, tc.driverNumber, 9472); got != tc.want {
+				t.Errorf("best_lap_duration de %d en 9472 = %+v, se esperaba %+v", tc.driverNumber, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"net/url"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// postgres permite compartir una misma base entre varias instancias del proxy
+type postgres struct{}
+
+func init() {
+	register(postgres{})
+}
+
+func (postgres) Name() string { return "postgres" }
+
+func (postgres) DriverName() string { return "postgres" }
+
+// Rebind convierte los ? en los placeholders numerados de PostgreSQL ($1, $2, ...)
+func (postgres) Rebind(query string) string { return rebindNumbered(query) }
+
+// BulkLoad no necesita configuración: PostgreSQL ya maneja escrituras concurrentes
+func (postgres) BulkLoad() (begin, end []string) {
+	return nil, nil
+}
+
+// ReadOnlyDSN hace que todas las transacciones de la conexión sean de solo lectura.
+// dsn puede ser una URL (postgres://...) o una lista de pares clave=valor; lib/pq manda
+// los parámetros que no conoce como configuración de la sesión.
+func (postgres) ReadOnlyDSN(dsn string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			q := u.Query()
+			q.Set("default_transaction_read_only", "on")
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+	}
+	return strings.TrimSpace(dsn + " default_transaction_read_only=on")
+}
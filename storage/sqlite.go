@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"net/url"
+	"strings"
+)
+
+// sqlite guarda los datos en un archivo local; es el motor por defecto. El driver
+// depende de cómo se compile (ver sqlite_cgo.go y sqlite_nocgo.go), pero el esquema
+// y las consultas son los mismos con cualquiera de los dos.
+type sqlite struct{}
+
+func init() {
+	register(sqlite{})
+}
+
+func (sqlite) Name() string { return "sqlite" }
+
+func (sqlite) DriverName() string { return sqliteDriver }
+
+// Rebind no cambia nada: SQLite acepta los placeholders ?
+func (sqlite) Rebind(query string) string { return query }
+
+// BulkLoad activa WAL y espera hasta 10 s ante una base bloqueada mientras dura la
+// carga, y al terminar vuelve a la configuración por defecto de SQLite
+func (sqlite) BulkLoad() (begin, end []string) {
+	return []string{"PRAGMA journal_mode=WAL;", "PRAGMA busy_timeout=10000;"},
+		[]string{"PRAGMA journal_mode=DELETE;", "PRAGMA busy_timeout=0;"}
+}
+
+// ReadOnlyDSN abre el archivo como URI con mode=ro, que no crea el archivo si no existe.
+// Un DSN que ya es una URI (file:...) conserva sus parámetros.
+func (sqlite) ReadOnlyDSN(dsn string) string {
+	if !strings.HasPrefix(dsn, "file:") {
+		dsn = "file:" + (&url.URL{Path: dsn}).EscapedPath()
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&mode=ro"
+	}
+	return dsn + "?mode=ro"
+}
@@ -0,0 +1,10 @@
+//go:build cgo && !nocgo
+
+package storage
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteDriver es mattn/go-sqlite3, que compila la librería de SQLite en C con CGO
+const sqliteDriver = "sqlite3"
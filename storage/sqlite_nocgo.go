@@ -0,0 +1,11 @@
+//go:build !cgo || nocgo
+
+package storage
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// sqliteDriver es modernc.org/sqlite, una traducción de SQLite a Go que no necesita
+// CGO ni un compilador de C. Se usa al compilar con CGO_ENABLED=0 o con -tags nocgo.
+const sqliteDriver = "sqlite"
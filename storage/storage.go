@@ -0,0 +1,226 @@
+// Package storage abre la base de datos del proxy con el motor configurado (SQLite o
+// PostgreSQL) y resuelve lo que cambia entre ellos: el driver, el formato de los
+// placeholders y la configuración para las cargas masivas. El resto del código escribe
+// sus consultas una sola vez, con placeholders ? y SQL que ambos motores aceptan.
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultSQLiteDSN es el archivo que usa SQLite si no se indica otro
+const DefaultSQLiteDSN = "./proxy.db"
+
+// Backend es lo que cambia entre motores de base de datos
+type Backend interface {
+	// Name identifica al motor ("sqlite" o "postgres"); las migraciones de cada motor
+	// están en el directorio con este nombre
+	Name() string
+	// DriverName es el nombre con el que el driver se registra en database/sql
+	DriverName() string
+	// Rebind convierte los placeholders ? de query al formato del motor
+	Rebind(query string) string
+	// BulkLoad devuelve las sentencias que preparan la conexión para una carga masiva
+	// y las que restauran la configuración al terminar
+	BulkLoad() (begin, end []string)
+	// ReadOnlyDSN devuelve dsn con las opciones para abrir la base en solo lectura
+	ReadOnlyDSN(dsn string) string
+}
+
+// backends son los motores soportados, por nombre
+var backends = map[string]Backend{}
+
+func register(backend Backend) {
+	backends[backend.Name()] = backend
+}
+
+// DB es una conexión a la base que reescribe los placeholders de cada consulta para
+// el motor. Se usa igual que *sql.DB.
+type DB struct {
+	*sql.DB
+	Backend
+}
+
+// Open abre la base con el motor backend ("sqlite" o "postgres") y el DSN dado:
+// la ruta del archivo para SQLite (DefaultSQLiteDSN si viene vacío) o la cadena de
+// conexión para PostgreSQL (si viene vacía se usan PGHOST, PGUSER, etc.)
+func Open(backend, dsn string) (*DB, error) {
+	return open(backend, dsn, false)
+}
+
+// OpenReadOnly es Open en solo lectura: cualquier escritura falla y, con SQLite, el
+// archivo tiene que existir (Open lo crea)
+func OpenReadOnly(backend, dsn string) (*DB, error) {
+	return open(backend, dsn, true)
+}
+
+func open(backend, dsn string, readOnly bool) (*DB, error) {
+	b, ok := backends[backend]
+	if !ok {
+		return nil, fmt.Errorf("motor de base de datos desconocido: %s (usar sqlite o postgres)", backend)
+	}
+	if dsn == "" && backend == "sqlite" {
+		dsn = DefaultSQLiteDSN
+	}
+	if readOnly {
+		dsn = b.ReadOnlyDSN(dsn)
+	}
+	conn, err := sql.Open(b.DriverName(), dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("no se pudo conectar a la base %s: %v", backend, err)
+	}
+	return &DB{DB: conn, Backend: b}, nil
+}
+
+// Exec, Query, QueryRow, Prepare y sus variantes con contexto son los de *sql.DB con
+// los placeholders de query reescritos para el motor
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.DB.Exec(db.Rebind(query), args...)
+}
+
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.DB.Query(db.Rebind(query), args...)
+}
+
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.DB.QueryRow(db.Rebind(query), args...)
+}
+
+func (db *DB) Prepare(query string) (*sql.Stmt, error) {
+	return db.DB.Prepare(db.Rebind(query))
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.DB.ExecContext(ctx, db.Rebind(query), args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return db.DB.QueryContext(ctx, db.Rebind(query), args...)
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return db.DB.QueryRowContext(ctx, db.Rebind(query), args...)
+}
+
+func (db *DB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return db.DB.PrepareContext(ctx, db.Rebind(query))
+}
+
+// Begin inicia una transacción que también reescribe los placeholders
+func (db *DB) Begin() (*Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+// BeginTx es Begin con contexto y opciones de aislamiento
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, backend: db.Backend}, nil
+}
+
+// BeginBulkLoad prepara la base para una carga masiva (en SQLite, modo WAL y espera
+// ante bloqueos). Si falla la carga igual puede hacerse, solo que más lenta.
+func (db *DB) BeginBulkLoad() error {
+	begin, _ := db.BulkLoad()
+	return db.execAll(begin)
+}
+
+// EndBulkLoad restaura la configuración que cambió BeginBulkLoad
+func (db *DB) EndBulkLoad() error {
+	_, end := db.BulkLoad()
+	return db.execAll(end)
+}
+
+func (db *DB) execAll(statements []string) error {
+	for _, statement := range statements {
+		if _, err := db.DB.Exec(statement); err != nil {
+			return fmt.Errorf("error ejecutando %q: %v", statement, err)
+		}
+	}
+	return nil
+}
+
+// Tx es una transacción que reescribe los placeholders de cada consulta para el motor
+type Tx struct {
+	*sql.Tx
+	backend Backend
+}
+
+// Exec, Query, QueryRow, Prepare y sus variantes con contexto son los de *sql.Tx con
+// los placeholders de query reescritos para el motor
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.Exec(tx.backend.Rebind(query), args...)
+}
+
+func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.Query(tx.backend.Rebind(query), args...)
+}
+
+func (tx *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRow(tx.backend.Rebind(query), args...)
+}
+
+func (tx *Tx) Prepare(query string) (*sql.Stmt, error) {
+	return tx.Tx.Prepare(tx.backend.Rebind(query))
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.ExecContext(ctx, tx.backend.Rebind(query), args...)
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.QueryContext(ctx, tx.backend.Rebind(query), args...)
+}
+
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRowContext(ctx, tx.backend.Rebind(query), args...)
+}
+
+func (tx *Tx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return tx.Tx.PrepareContext(ctx, tx.backend.Rebind(query))
+}
+
+// rebindNumbered reemplaza cada ? por $1, $2, ... en orden. Los ? dentro de literales,
+// identificadores entre comillas y comentarios -- se dejan como están.
+func rebindNumbered(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'' || ch == '"':
+			end := strings.IndexByte(query[i+1:], ch)
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+2])
+			i += end + 1
+		case ch == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+1])
+			i += end
+		case ch == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
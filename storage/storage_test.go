@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRebindNumbered(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"sin placeholders", "SELECT 1", "SELECT 1"},
+		{"en orden", "SELECT * FROM Driver WHERE team_name = ? AND country_code = ?", "SELECT * FROM Driver WHERE team_name = $1 AND country_code = $2"},
+		{"repetidos", "WHERE a = ? OR b = ? OR c IN (?, ?)", "WHERE a = $1 OR b = $2 OR c IN ($3, $4)"},
+		{"pegados", "VALUES (?,?,?)", "VALUES ($1,$2,$3)"},
+		{"en un literal", "WHERE name = '¿quién?' AND key = ?", "WHERE name = '¿quién?' AND key = $1"},
+		{"literal con comilla escapada", "WHERE name = 'it''s ?' AND key = ?", "WHERE name = 'it''s ?' AND key = $1"},
+		{"literal vacío", "WHERE name = '' AND key = ?", "WHERE name = '' AND key = $1"},
+		{"identificador entre comillas", `SELECT "col?" FROM t WHERE a = ?`, `SELECT "col?" FROM t WHERE a = $1`},
+		{"comentario", "SELECT ? -- ¿y esto?\nFROM t WHERE a = ?", "SELECT $1 -- ¿y esto?\nFROM t WHERE a = $2"},
+		{"comentario al final", "SELECT ? -- ?", "SELECT $1 -- ?"},
+		{"literal sin cerrar", "SELECT ? WHERE a = 'x?", "SELECT $1 WHERE a = 'x?"},
+		{"resta", "SELECT ? - 1", "SELECT $1 - 1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rebindNumbered(tc.query); got != tc.want {
+				t.Errorf("rebindNumbered(%q)\n = %q\n se esperaba %q", tc.query, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReadOnlyDSN(t *testing.T) {
+	tests := []struct {
+		backend Backend
+		dsn     string
+		want    string
+	}{
+		{sqlite{}, "./proxy.db", "file:./proxy.db?mode=ro"},
+		{sqlite{}, "/datos/f1 stats.db", "file:/datos/f1%20stats.db?mode=ro"},
+		{sqlite{}, "file:proxy.db?cache=shared", "file:proxy.db?cache=shared&mode=ro"},
+		{postgres{}, "postgres://f1:f1@localhost:5432/f1?sslmode=disable", "postgres://f1:f1@localhost:5432/f1?default_transaction_read_only=on&sslmode=disable"},
+		{postgres{}, "host=localhost dbname=f1", "host=localhost dbname=f1 default_transaction_read_only=on"},
+		{postgres{}, "", "default_transaction_read_only=on"},
+	}
+	for _, tc := range tests {
+		if got := tc.backend.ReadOnlyDSN(tc.dsn); got != tc.want {
+			t.Errorf("%s ReadOnlyDSN(%q) = %q, se esperaba %q", tc.backend.Name(), tc.dsn, got, tc.want)
+		}
+	}
+}
+
+// TestOpenReadOnly comprueba que OpenReadOnly no cree el archivo de SQLite y que la
+// conexión lea pero no escriba
+func TestOpenReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proxy.db")
+
+	if db, err := OpenReadOnly("sqlite", path); err == nil {
+		db.Close()
+		t.Fatal("OpenReadOnly abrió un archivo inexistente")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("OpenReadOnly creó el archivo: %v", err)
+	}
+
+	db, err := Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE Driver (driver_number INTEGER PRIMARY KEY)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO Driver (driver_number) VALUES (?)`, 1); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	ro, err := OpenReadOnly("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ro.Close()
+	var drivers int
+	if err := ro.QueryRow(`SELECT COUNT(*) FROM Driver`).Scan(&drivers); err != nil || drivers != 1 {
+		t.Errorf("COUNT(*) = %d, %v; se esperaba 1", drivers, err)
+	}
+	if _, err := ro.Exec(`INSERT INTO Driver (driver_number) VALUES (?)`, 2); err == nil {
+		t.Error("la conexión de solo lectura permitió un INSERT")
+	}
+	if _, err := ro.Exec(`CREATE TABLE IF NOT EXISTS schema_version (version INTEGER)`); err == nil {
+		t.Error("la conexión de solo lectura permitió un CREATE TABLE")
+	}
+}
@@ -0,0 +1,197 @@
+// Package storagetest abre bases de prueba con el esquema migrado y carga un conjunto de
+// datos chico y conocido, para los tests de los paquetes que leen la base. Por defecto la
+// base es SQLite en memoria; si F1_TEST_POSTGRES_DSN tiene una cadena de conexión, cada
+// test usa un esquema nuevo de esa base PostgreSQL, que se borra al terminar.
+package storagetest
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"f1_statshub_system/migrations"
+	"f1_statshub_system/stats"
+	"f1_statshub_system/storage"
+)
+
+// EnvPostgresDSN es la variable de entorno con la base PostgreSQL de los tests
+const EnvPostgresDSN = "F1_TEST_POSTGRES_DSN"
+
+// schemas numera los esquemas PostgreSQL creados por este proceso
+var schemas int64
+
+// Open devuelve una base vacía con todas las migraciones aplicadas, que se cierra
+// (y en PostgreSQL se borra) al terminar t
+func Open(t testing.TB) *storage.DB {
+	t.Helper()
+
+	var db *storage.DB
+	if dsn := os.Getenv(EnvPostgresDSN); dsn != "" {
+		db = openPostgres(t, dsn)
+	} else {
+		var err error
+		db, err = storage.Open("sqlite", ":memory:")
+		if err != nil {
+			t.Fatalf("no se pudo abrir SQLite en memoria: %v", err)
+		}
+		// Cada conexión a :memory: es una base distinta
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+	}
+
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatalf("no se pudieron aplicar las migraciones: %v", err)
+	}
+	return db
+}
+
+// openPostgres crea un esquema propio del test en la base dsn y devuelve una conexión
+// que lo usa como search_path
+func openPostgres(t testing.TB, dsn string) *storage.DB {
+	t.Helper()
+
+	admin, err := storage.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("no se pudo abrir %s: %v", EnvPostgresDSN, err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("f1test_%d_%d", os.Getpid(), atomic.AddInt64(&schemas, 1))
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("no se pudo crear el esquema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("no se pudo borrar el esquema %s: %v", schema, err)
+		}
+	})
+
+	db, err := storage.Open("postgres", withSearchPath(dsn, schema))
+	if err != nil {
+		t.Fatalf("no se pudo abrir el esquema %s: %v", schema, err)
+	}
+	// Se registra después del DROP SCHEMA, así que se cierra antes
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// withSearchPath agrega search_path a dsn, que puede ser una URL (postgres://...) o una
+// lista de pares clave=valor
+func withSearchPath(dsn, schema string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			q := u.Query()
+			q.Set("search_path", schema)
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+	}
+	return dsn + " search_path=" + schema
+}
+
+// OpenSeeded devuelve una base migrada con los datos de Seed
+func OpenSeeded(t testing.TB) *storage.DB {
+	t.Helper()
+	db := Open(t)
+	Seed(t, db)
+	return db
+}
+
+// Seed carga en db tres pilotos, dos circuitos y tres carreras con vueltas y posiciones,
+// y recalcula las vueltas rápidas, los récords por circuito y las estadísticas con
+// stats.Rebuild. Los datos cubren los casos que los handlers distinguen:
+//   - 7953 (Sakhir 2023): carrera completa sin cambios de posición.
+//   - 9472 (Sakhir 2024): la vuelta 3 de Norris no tiene lap_duration, sector 2 ni
+//     velocidad, y la vuelta 2 de Leclerc tiene lap_duration estimado (94.3, más rápida
+//     que la vuelta rápida real de Verstappen, 94.5).
+//   - 9480 (Jeddah 2024): dos cambios de líder; Jeddah no tiene lap_length_km.
+func Seed(t testing.TB, db *storage.DB) {
+	t.Helper()
+
+	for _, statement := range seedStatements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("no se pudieron cargar los datos de prueba: %v\n%s", err, statement)
+		}
+	}
+	if err := stats.Rebuild(db); err != nil {
+		t.Fatalf("no se pudieron recalcular las estadísticas: %v", err)
+	}
+}
+
+// seedStatements son los INSERT de Seed. Las vueltas de cada piloto empiezan cuando
+// termina la anterior, para que la vista LapPosition calcule las posiciones por vuelta.
+var seedStatements = []string{
+	`INSERT INTO Driver (driver_number, first_name, last_name, name_acronym, team_name, country_code) VALUES
+	(1, 'Max', 'Verstappen', 'VER', 'Red Bull Racing', 'NED'),
+	(16, 'Charles', 'Leclerc', 'LEC', 'Ferrari', 'MON'),
+	(4, 'Lando', 'Norris', 'NOR', 'McLaren', 'GBR')`,
+
+	`INSERT INTO Circuit (circuit_key, circuit_short_name, location, country_name, lap_length_km) VALUES
+	(63, 'Sakhir', 'Sakhir', 'Bahrain', 5.412),
+	(149, 'Jeddah', 'Jeddah', 'Saudi Arabia', NULL)`,
+
+	`INSERT INTO Session (session_key, session_name, session_type, location, country_name, year, circuit_key, circuit_short_name, date_start) VALUES
+	(7953, 'Race', 'Race', 'Sakhir', 'Bahrain', 2023, 63, 'Sakhir', '2023-03-05T15:00:00+00:00'),
+	(9472, 'Race', 'Race', 'Sakhir', 'Bahrain', 2024, 63, 'Sakhir', '2024-03-02T15:00:00+00:00'),
+	(9480, 'Race', 'Race', 'Jeddah', 'Saudi Arabia', 2024, 149, 'Jeddah', '2024-03-09T17:00:00+00:00')`,
+
+	`INSERT INTO Laps (driver_number, session_key, lap_number, lap_duration, lap_duration_estimated, duration_sector_1, duration_sector_2, duration_sector_3, st_speed, date_start) VALUES
+	(1, 7953, 1, 96.0, 0, 31.0, 33.5, 31.5, 306.0, '2023-03-05T15:00:00.000000+00:00'),
+	(1, 7953, 2, 95.2, 0, 30.7, 33.2, 31.3, 309.5, '2023-03-05T15:01:36.000000+00:00'),
+	(16, 7953, 1, 96.5, 0, 31.2, 33.7, 31.6, 305.2, '2023-03-05T15:00:00.000000+00:00'),
+	(16, 7953, 2, 95.6, 0, 30.9, 33.3, 31.4, 308.1, '2023-03-05T15:01:36.500000+00:00'),
+	(4, 7953, 1, 96.9, 0, 31.3, 33.9, 31.7, 304.4, '2023-03-05T15:00:00.000000+00:00'),
+	(4, 7953, 2, 95.9, 0, 31.0, 33.4, 31.5, 307.3, '2023-03-05T15:01:36.900000+00:00'),
+	(1, 9472, 1, 97.0, 0, 31.0, 34.0, 32.0, 305.0, '2024-03-02T15:00:00.000000+00:00'),
+	(1, 9472, 2, 95.0, 0, 30.5, 33.0, 31.5, 310.2, '2024-03-02T15:01:37.000000+00:00'),
+	(1, 9472, 3, 94.5, 0, 30.2, 33.1, 31.2, 312.4, '2024-03-02T15:03:12.000000+00:00'),
+	(4, 9472, 1, 97.3, 0, 31.1, 34.1, 32.1, 304.0, '2024-03-02T15:00:00.000000+00:00'),
+	(4, 9472, 2, 94.8, 0, 30.4, 33.0, 31.4, 311.0, '2024-03-02T15:01:37.300000+00:00'),
+	(4, 9472, 3, NULL, 0, 30.9, NULL, 31.6, NULL, '2024-03-02T15:03:12.100000+00:00'),
+	(16, 9472, 1, 97.6, 0, 31.2, 34.2, 32.2, 303.1, '2024-03-02T15:00:00.000000+00:00'),
+	(16, 9472, 2, 94.3, 1, 30.1, 33.0, 31.2, 309.0, '2024-03-02T15:01:37.600000+00:00'),
+	(16, 9472, 3, 95.1, 0, 30.6, 33.2, 31.3, 308.5, '2024-03-02T15:03:11.900000+00:00'),
+	(16, 9480, 1, 92.0, 0, 29.0, 32.0, 31.0, 320.1, '2024-03-09T17:00:00.000000+00:00'),
+	(16, 9480, 2, 90.1, 0, 28.5, 31.2, 30.4, 322.0, '2024-03-09T17:01:32.000000+00:00'),
+	(16, 9480, 3, 90.5, 0, 28.6, 31.4, 30.5, 321.4, '2024-03-09T17:03:02.100000+00:00'),
+	(1, 9480, 1, 92.2, 0, 29.1, 32.1, 31.0, 319.0, '2024-03-09T17:00:00.000000+00:00'),
+	(1, 9480, 2, 89.9, 0, 28.4, 31.1, 30.4, 323.6, '2024-03-09T17:01:32.200000+00:00'),
+	(1, 9480, 3, 90.0, 0, 28.4, 31.2, 30.4, 322.9, '2024-03-09T17:03:02.100000+00:00'),
+	(4, 9480, 1, 92.5, 0, 29.2, 32.2, 31.1, 318.2, '2024-03-09T17:00:00.000000+00:00'),
+	(4, 9480, 2, 90.8, 0, 28.7, 31.5, 30.6, 321.1, '2024-03-09T17:01:32.500000+00:00'),
+	(4, 9480, 3, 91.0, 0, 28.8, 31.6, 30.6, 320.6, '2024-03-09T17:03:03.300000+00:00')
+`,
+
+	`INSERT INTO PositionHistory (driver_number, session_key, position, date) VALUES
+	(1, 7953, 1, '2023-03-05T15:00:00.000000+00:00'),
+	(16, 7953, 2, '2023-03-05T15:00:00.000000+00:00'),
+	(4, 7953, 3, '2023-03-05T15:00:00.000000+00:00'),
+	(1, 9472, 1, '2024-03-02T15:00:00.000000+00:00'),
+	(16, 9472, 2, '2024-03-02T15:00:00.000000+00:00'),
+	(4, 9472, 3, '2024-03-02T15:00:00.000000+00:00'),
+	(4, 9472, 2, '2024-03-02T15:02:30.000000+00:00'),
+	(16, 9472, 3, '2024-03-02T15:02:30.000000+00:00'),
+	(16, 9480, 1, '2024-03-09T17:00:00.000000+00:00'),
+	(1, 9480, 2, '2024-03-09T17:00:00.000000+00:00'),
+	(4, 9480, 3, '2024-03-09T17:00:00.000000+00:00'),
+	(1, 9480, 1, '2024-03-09T17:02:30.000000+00:00'),
+	(16, 9480, 2, '2024-03-09T17:02:30.000000+00:00'),
+	(16, 9480, 1, '2024-03-09T17:03:20.000000+00:00'),
+	(1, 9480, 2, '2024-03-09T17:03:20.000000+00:00')
+`,
+
+	`INSERT INTO Position (driver_number, session_key, position, date) VALUES
+	(1, 7953, 1, '2023-03-05T15:00:00.000000+00:00'),
+	(16, 7953, 2, '2023-03-05T15:00:00.000000+00:00'),
+	(4, 7953, 3, '2023-03-05T15:00:00.000000+00:00'),
+	(1, 9472, 1, '2024-03-02T15:00:00.000000+00:00'),
+	(4, 9472, 2, '2024-03-02T15:02:30.000000+00:00'),
+	(16, 9472, 3, '2024-03-02T15:02:30.000000+00:00'),
+	(16, 9480, 1, '2024-03-09T17:03:20.000000+00:00'),
+	(1, 9480, 2, '2024-03-09T17:03:20.000000+00:00'),
+	(4, 9480, 3, '2024-03-09T17:00:00.000000+00:00')`,
+}
@@ -0,0 +1,62 @@
+package storagetest
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestPostgres comprueba, si F1_TEST_POSTGRES_DSN está definida, que Open use un esquema
+// propio del test con las migraciones aplicadas. El resto de los tests que abren la base
+// con este paquete (migrations, repository, api) corren entonces contra PostgreSQL.
+func TestPostgres(t *testing.T) {
+	if os.Getenv(EnvPostgresDSN) == "" {
+		t.Skip(EnvPostgresDSN + " no está definida")
+	}
+
+	db := OpenSeeded(t)
+	if db.Name() != "postgres" {
+		t.Fatalf("Open devolvió una base %s", db.Name())
+	}
+
+	var schema string
+	if err := db.QueryRow(`SELECT current_schema()`).Scan(&schema); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(schema, "f1test_") {
+		t.Errorf("la base usa el esquema %q, se esperaba uno f1test_", schema)
+	}
+
+	var drivers int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM Driver WHERE driver_number IN (?, ?, ?)`, 1, 4, 16).Scan(&drivers); err != nil {
+		t.Fatal(err)
+	}
+	if drivers != 3 {
+		t.Errorf("hay %d pilotos de Seed, se esperaban 3", drivers)
+	}
+
+	// Otro test ve su propio esquema vacío
+	other := Open(t)
+	if err := other.QueryRow(`SELECT COUNT(*) FROM Driver`).Scan(&drivers); err != nil {
+		t.Fatal(err)
+	}
+	if drivers != 0 {
+		t.Errorf("el segundo esquema tiene %d pilotos, se esperaba 0", drivers)
+	}
+}
+
+func TestWithSearchPath(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want string
+	}{
+		{"postgres://f1:f1@localhost:5432/f1?sslmode=disable", "postgres://f1:f1@localhost:5432/f1?search_path=f1test_1_1&sslmode=disable"},
+		{"postgresql://localhost/f1", "postgresql://localhost/f1?search_path=f1test_1_1"},
+		{"host=localhost dbname=f1", "host=localhost dbname=f1 search_path=f1test_1_1"},
+	}
+	for _, tc := range tests {
+		if got := withSearchPath(tc.dsn, "f1test_1_1"); got != tc.want {
+			t.Errorf("withSearchPath(%q) = %q, se esperaba %q", tc.dsn, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxMarkdownIssues es la cantidad de problemas que se listan por verificación en
+// markdown; el JSON los incluye todos
+const maxMarkdownIssues = 20
+
+// ExitCode es el código con el que termina el comando verify: 1 si hay problemas
+// críticos y 0 si no
+func (r *Report) ExitCode() int {
+	if r.Critical > 0 {
+		return 1
+	}
+	return 0
+}
+
+// WriteJSON escribe el reporte como JSON indentado
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteMarkdown escribe el reporte como markdown: un resumen con una fila por
+// verificación y el detalle de las que encontraron problemas
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Reporte de calidad de datos\n\n")
+	fmt.Fprintf(&b, "Generado: %s (%s)\n\n", r.GeneratedAt, r.Backend)
+	fmt.Fprintf(&b, "**%d problema(s) crítico(s), %d advertencia(s)**\n\n", r.Critical, r.Warnings)
+
+	b.WriteString("| Verificación | Severidad | Problemas |\n")
+	b.WriteString("|---|---|---|\n")
+	for _, c := range r.Checks {
+		fmt.Fprintf(&b, "| %s | %s | %d |\n", c.Description, severityLabel(c.Severity), c.Count)
+	}
+
+	for _, c := range r.Checks {
+		if c.Count == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s (%s)\n\n", c.Description, severityLabel(c.Severity))
+		b.WriteString("| Carrera | Piloto | Vuelta | Detalle |\n")
+		b.WriteString("|---|---|---|---|\n")
+		for i, issue := range c.Issues {
+			if i == maxMarkdownIssues {
+				fmt.Fprintf(&b, "\n... y %d más (ver el reporte en JSON)\n", c.Count-maxMarkdownIssues)
+				break
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				optionalInt(issue.SessionKey), optionalInt(issue.DriverNumber), optionalInt(issue.LapNumber),
+				strings.ReplaceAll(issue.Detail, "|", "\\|"))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func severityLabel(s Severity) string {
+	if s == Critical {
+		return "crítico"
+	}
+	return "advertencia"
+}
+
+func optionalInt(v *int) string {
+	if v == nil {
+		return "-"
+	}
+	return strconv.Itoa(*v)
+}
@@ -0,0 +1,444 @@
+// Package verify revisa la calidad de los datos ingeridos: busca carreras sin vueltas,
+// pilotos sin posición final, vueltas cuyos sectores no cierran, fechas duplicadas o fuera
+// de lugar, pilotos que faltan en Driver, registros del piloto de reserva y otros registros
+// rechazados por la ingesta. Solo lee la base; el resultado es un Report que se puede escribir como JSON o markdown.
+package verify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"f1_statshub_system/ingest"
+	"f1_statshub_system/storage"
+)
+
+// Severity indica qué tan grave es un problema. Los críticos afectan las estadísticas que
+// entrega la API; las advertencias son datos incompletos que la API ya tolera.
+type Severity string
+
+const (
+	Critical Severity = "critical"
+	Warning  Severity = "warning"
+)
+
+const (
+	// sectorTolerance es la diferencia máxima (en segundos) admitida entre lap_duration y
+	// la suma de los sectores, por el redondeo de OpenF1
+	sectorTolerance = 0.5
+	// maxRaceDuration es cuánto después del comienzo de la carrera puede empezar una vuelta
+	// (el reglamento limita las carreras a 3 horas, más las interrupciones)
+	maxRaceDuration = 4 * time.Hour
+	// earlyStart es cuánto antes del comienzo programado puede empezar la primera vuelta
+	earlyStart = 10 * time.Minute
+)
+
+// Issue es un problema encontrado. Los campos que no aplican a la verificación quedan nil.
+type Issue struct {
+	SessionKey   *int   `json:"session_key,omitempty"`
+	DriverNumber *int   `json:"driver_number,omitempty"`
+	LapNumber    *int   `json:"lap_number,omitempty"`
+	Detail       string `json:"detail"`
+}
+
+// Check es el resultado de una verificación
+type Check struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Severity    Severity `json:"severity"`
+	Count       int      `json:"count"`
+	Issues      []Issue  `json:"issues"`
+}
+
+// Report es el resultado de todas las verificaciones
+type Report struct {
+	GeneratedAt string  `json:"generated_at"`
+	Backend     string  `json:"backend"`
+	Critical    int     `json:"critical"`
+	Warnings    int     `json:"warnings"`
+	Checks      []Check `json:"checks"`
+}
+
+// check es una verificación: run devuelve los problemas encontrados
+type check struct {
+	name        string
+	description string
+	severity    Severity
+	run         func(ctx context.Context, db *storage.DB) ([]Issue, error)
+}
+
+var checks = []check{
+	{"sessions_without_laps", "Carreras sin vueltas registradas", Critical, sessionsWithoutLaps},
+	{"impossible_sector_sums", "Vueltas cuyo tiempo no coincide con la suma de sus sectores", Critical, impossibleSectorSums},
+	{"missing_drivers", "Pilotos con vueltas o posiciones que no están en Driver", Warning, missingDrivers},
+	{"drivers_without_position", "Pilotos con vueltas pero sin posición final", Warning, driversWithoutPosition},
+	{"duplicate_lap_timestamps", "Vueltas de un mismo piloto con la misma fecha de inicio", Warning, duplicateLapTimestamps},
+	{"duplicate_positions", "Dos pilotos en la misma posición en el mismo instante", Warning, duplicatePositions},
+	{"anomalous_lap_timestamps", "Vueltas con fecha de inicio fuera de la carrera o fuera de orden", Warning, anomalousLapTimestamps},
+	{"reserved_driver_records", "Posiciones y vueltas del piloto de reserva descartadas al ingerir", Warning, reservedDriverRecords},
+	{"rejected_records", "Registros de OpenF1 descartados al ingerir", Warning, rejectedRecords},
+}
+
+// Run ejecuta todas las verificaciones sobre db
+func Run(ctx context.Context, db *storage.DB) (*Report, error) {
+	report := &Report{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Backend:     db.Name(),
+		Checks:      []Check{},
+	}
+	for _, c := range checks {
+		issues, err := c.run(ctx, db)
+		if err != nil {
+			return nil, fmt.Errorf("error en la verificación %s: %v", c.name, err)
+		}
+		if issues == nil {
+			issues = []Issue{}
+		}
+		report.Checks = append(report.Checks, Check{
+			Name:        c.name,
+			Description: c.description,
+			Severity:    c.severity,
+			Count:       len(issues),
+			Issues:      issues,
+		})
+		if c.severity == Critical {
+			report.Critical += len(issues)
+		} else {
+			report.Warnings += len(issues)
+		}
+	}
+	return report, nil
+}
+
+func sessionsWithoutLaps(ctx context.Context, db *storage.DB) ([]Issue, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT s.session_key, s.year, s.circuit_short_name
+		FROM Session s
+		WHERE NOT EXISTS (SELECT 1 FROM Laps l WHERE l.session_key = s.session_key)
+		ORDER BY s.date_start ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var sessionKey, year int
+		var circuit string
+		if err := rows.Scan(&sessionKey, &year, &circuit); err != nil {
+			return nil, err
+		}
+		issues = append(issues, Issue{
+			SessionKey: &sessionKey,
+			Detail:     fmt.Sprintf("%s %d no tiene vueltas", circuit, year),
+		})
+	}
+	return issues, rows.Err()
+}
+
+// missingDrivers busca pilotos que aparecen en los datos de alguna carrera pero no en
+// Driver. La ingesta solo guarda los pilotos de las sesiones de referencia (y descarta al
+// de reserva), así que es esperable; las consultas de la API los omiten al unir con Driver.
+func missingDrivers(ctx context.Context, db *storage.DB) ([]Issue, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT x.driver_number, COUNT(DISTINCT x.session_key)
+		FROM (
+			SELECT driver_number, session_key FROM Laps
+			UNION
+			SELECT driver_number, session_key FROM Position
+			UNION
+			SELECT driver_number, session_key FROM PositionHistory
+		) x
+		WHERE NOT EXISTS (SELECT 1 FROM Driver d WHERE d.driver_number = x.driver_number)
+		GROUP BY x.driver_number
+		ORDER BY x.driver_number ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var driverNumber, sessions int
+		if err := rows.Scan(&driverNumber, &sessions); err != nil {
+			return nil, err
+		}
+		issues = append(issues, Issue{
+			DriverNumber: &driverNumber,
+			Detail:       fmt.Sprintf("aparece en %d carrera(s) y no está en Driver", sessions),
+		})
+	}
+	return issues, rows.Err()
+}
+
+func impossibleSectorSums(ctx context.Context, db *storage.DB) ([]Issue, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT session_key, driver_number, lap_number, lap_duration,
+			duration_sector_1 + duration_sector_2 + duration_sector_3
+		FROM Laps
+		WHERE lap_duration IS NOT NULL
+		AND duration_sector_1 IS NOT NULL
+		AND duration_sector_2 IS NOT NULL
+		AND duration_sector_3 IS NOT NULL
+		AND ABS(lap_duration - (duration_sector_1 + duration_sector_2 + duration_sector_3)) > ?
+		ORDER BY session_key, driver_number, lap_number
+	`, sectorTolerance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var issue Issue
+		var sessionKey, driverNumber, lapNumber int
+		var duration, sectors float64
+		if err := rows.Scan(&sessionKey, &driverNumber, &lapNumber, &duration, &sectors); err != nil {
+			return nil, err
+		}
+		issue.SessionKey, issue.DriverNumber, issue.LapNumber = &sessionKey, &driverNumber, &lapNumber
+		issue.Detail = fmt.Sprintf("lap_duration %.3f s, suma de sectores %.3f s", duration, sectors)
+		issues = append(issues, issue)
+	}
+	return issues, rows.Err()
+}
+
+func driversWithoutPosition(ctx context.Context, db *storage.DB) ([]Issue, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT l.session_key, l.driver_number, COUNT(*)
+		FROM Laps l
+		WHERE NOT EXISTS (
+			SELECT 1 FROM Position p
+			WHERE p.session_key = l.session_key AND p.driver_number = l.driver_number
+		)
+		GROUP BY l.session_key, l.driver_number
+		ORDER BY l.session_key, l.driver_number
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var sessionKey, driverNumber, laps int
+		if err := rows.Scan(&sessionKey, &driverNumber, &laps); err != nil {
+			return nil, err
+		}
+		issues = append(issues, Issue{
+			SessionKey:   &sessionKey,
+			DriverNumber: &driverNumber,
+			Detail:       fmt.Sprintf("%d vuelta(s) sin posición final", laps),
+		})
+	}
+	return issues, rows.Err()
+}
+
+func duplicateLapTimestamps(ctx context.Context, db *storage.DB) ([]Issue, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT session_key, driver_number, date_start, COUNT(*)
+		FROM Laps
+		WHERE date_start IS NOT NULL
+		GROUP BY session_key, driver_number, date_start
+		HAVING COUNT(*) > 1
+		ORDER BY session_key, driver_number, date_start
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var sessionKey, driverNumber, laps int
+		var date string
+		if err := rows.Scan(&sessionKey, &driverNumber, &date, &laps); err != nil {
+			return nil, err
+		}
+		issues = append(issues, Issue{
+			SessionKey:   &sessionKey,
+			DriverNumber: &driverNumber,
+			Detail:       fmt.Sprintf("%d vueltas empiezan en %s", laps, date),
+		})
+	}
+	return issues, rows.Err()
+}
+
+func duplicatePositions(ctx context.Context, db *storage.DB) ([]Issue, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT session_key, date, position, COUNT(*)
+		FROM PositionHistory
+		GROUP BY session_key, date, position
+		HAVING COUNT(*) > 1
+		ORDER BY session_key, date, position
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var sessionKey, position, drivers int
+		var date string
+		if err := rows.Scan(&sessionKey, &date, &position, &drivers); err != nil {
+			return nil, err
+		}
+		issues = append(issues, Issue{
+			SessionKey: &sessionKey,
+			Detail:     fmt.Sprintf("%d pilotos en la posición %d en %s", drivers, position, date),
+		})
+	}
+	return issues, rows.Err()
+}
+
+// anomalousLapTimestamps compara las fechas en Go porque SQLite y PostgreSQL no comparten
+// funciones de fechas, y las columnas guardan el texto de OpenF1
+func anomalousLapTimestamps(ctx context.Context, db *storage.DB) ([]Issue, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT l.session_key, l.driver_number, l.lap_number, l.date_start, s.date_start
+		FROM Laps l
+		JOIN Session s ON s.session_key = l.session_key
+		WHERE l.date_start IS NOT NULL
+		ORDER BY l.session_key, l.driver_number, l.lap_number
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	var previous struct {
+		sessionKey, driverNumber, lapNumber int
+		start                               time.Time
+	}
+	for rows.Next() {
+		var sessionKey, driverNumber, lapNumber int
+		var rawLap, rawSession string
+		if err := rows.Scan(&sessionKey, &driverNumber, &lapNumber, &rawLap, &rawSession); err != nil {
+			return nil, err
+		}
+
+		var detail string
+		lapStart, err := time.Parse(time.RFC3339, rawLap)
+		sessionStart, sessionErr := time.Parse(time.RFC3339, rawSession)
+		switch {
+		case err != nil:
+			detail = fmt.Sprintf("fecha de inicio inválida: %q", rawLap)
+		case sessionErr != nil:
+			detail = fmt.Sprintf("la carrera tiene una fecha de inicio inválida: %q", rawSession)
+		case lapStart.Before(sessionStart.Add(-earlyStart)):
+			detail = fmt.Sprintf("empieza en %s, antes del comienzo de la carrera (%s)", rawLap, rawSession)
+		case lapStart.After(sessionStart.Add(maxRaceDuration)):
+			detail = fmt.Sprintf("empieza en %s, más de %v después del comienzo de la carrera (%s)", rawLap, maxRaceDuration, rawSession)
+		case previous.sessionKey == sessionKey && previous.driverNumber == driverNumber &&
+			previous.lapNumber == lapNumber-1 && !lapStart.After(previous.start):
+			detail = fmt.Sprintf("empieza en %s, no después de la vuelta anterior", rawLap)
+		}
+		if detail != "" {
+			issues = append(issues, Issue{SessionKey: &sessionKey, DriverNumber: &driverNumber, LapNumber: &lapNumber, Detail: detail})
+		}
+
+		previous.sessionKey, previous.driverNumber, previous.lapNumber = sessionKey, driverNumber, lapNumber
+		previous.start = lapStart
+		if err != nil {
+			previous.lapNumber = 0
+		}
+	}
+	return issues, rows.Err()
+}
+
+// reservedDriverRecords informa cuántos registros del piloto de reserva se descartaron en
+// cada solicitud: son esperables, así que van aparte de rejectedRecords y sin el detalle
+// de cada uno
+func reservedDriverRecords(ctx context.Context, db *storage.DB) ([]Issue, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT source, COUNT(*)
+		FROM RejectedRecord
+		WHERE reason = ?
+		GROUP BY source
+	`, ingest.ReservedDriverReason)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type rejected struct {
+		source  string
+		records int
+	}
+	var all []rejected
+	for rows.Next() {
+		var r rejected
+		if err := rows.Scan(&r.source, &r.records); err != nil {
+			return nil, err
+		}
+		all = append(all, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Ordenar en Go para que el orden no dependa de la intercalación de cada motor
+	sort.Slice(all, func(i, j int) bool { return all[i].source < all[j].source })
+
+	issues := make([]Issue, 0, len(all))
+	for _, r := range all {
+		issue := Issue{Detail: fmt.Sprintf("%d registro(s) de %s", r.records, r.source)}
+		if u, err := url.Parse(r.source); err == nil {
+			if sessionKey, err := strconv.Atoi(u.Query().Get("session_key")); err == nil {
+				issue.SessionKey = &sessionKey
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func rejectedRecords(ctx context.Context, db *storage.DB) ([]Issue, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT source, record_index, reason
+		FROM RejectedRecord
+		WHERE reason <> ?
+	`, ingest.ReservedDriverReason)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type rejected struct {
+		source string
+		index  int
+		reason string
+	}
+	var all []rejected
+	for rows.Next() {
+		var r rejected
+		if err := rows.Scan(&r.source, &r.index, &r.reason); err != nil {
+			return nil, err
+		}
+		all = append(all, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Ordenar en Go para que el orden no dependa de la intercalación de cada motor
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].source != all[j].source {
+			return all[i].source < all[j].source
+		}
+		return all[i].index < all[j].index
+	})
+
+	issues := make([]Issue, 0, len(all))
+	for _, r := range all {
+		issues = append(issues, Issue{Detail: fmt.Sprintf("registro %d de %s: %s", r.index, r.source, r.reason)})
+	}
+	return issues, nil
+}
@@ -0,0 +1,197 @@
+package verify
+
+import (
+	"context"
+	"testing"
+
+	"f1_statshub_system/ingest"
+	"f1_statshub_system/openf1"
+	"f1_statshub_system/storage/storagetest"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup []string
+		// rejections se guardan con ingest.SaveRejections, por fuente
+		rejections map[string][]openf1.Rejection
+		// want tiene la cantidad de problemas de cada verificación que debe fallar
+		want map[string]int
+	}{
+		{name: "seed sin problemas"},
+		{
+			name: "carrera sin vueltas",
+			setup: []string{
+				`INSERT INTO Session (session_key, session_name, session_type, location, country_name, year, circuit_key, circuit_short_name, date_start) VALUES
+				(9999, 'Race', 'Race', 'Jeddah', 'Saudi Arabia', 2025, 149, 'Jeddah', '2025-03-09T17:00:00+00:00')`,
+			},
+			want: map[string]int{"sessions_without_laps": 1},
+		},
+		{
+			name: "sectores que no suman",
+			setup: []string{
+				`UPDATE Laps SET lap_duration = 99.0 WHERE driver_number = 1 AND session_key = 7953 AND lap_number = 2`,
+			},
+			want: map[string]int{"impossible_sector_sums": 1},
+		},
+		{
+			name: "piloto que no está en Driver",
+			setup: []string{
+				`INSERT INTO Driver (driver_number, first_name, last_name, name_acronym, team_name, country_code) VALUES
+				(99, 'Test', 'Driver', 'TST', 'Test Team', NULL)`,
+				`INSERT INTO Laps (driver_number, session_key, lap_number, lap_duration, lap_duration_estimated, date_start) VALUES
+				(99, 7953, 1, NULL, 0, '2023-03-05T15:00:00.000000+00:00')`,
+				`INSERT INTO Position (driver_number, session_key, position, date) VALUES
+				(99, 7953, 4, '2023-03-05T15:00:00.000000+00:00')`,
+				`DELETE FROM Driver WHERE driver_number = 99`,
+			},
+			want: map[string]int{"missing_drivers": 1},
+		},
+		{
+			name: "piloto sin posición final",
+			setup: []string{
+				`DELETE FROM Position WHERE driver_number = 4 AND session_key = 7953`,
+			},
+			want: map[string]int{"drivers_without_position": 1},
+		},
+		{
+			// La vuelta 10 no sigue a la 3, así que no está fuera de orden
+			name: "vueltas con la misma fecha",
+			setup: []string{
+				`INSERT INTO Laps (driver_number, session_key, lap_number, lap_duration, lap_duration_estimated, date_start) VALUES
+				(1, 7953, 10, NULL, 0, '2023-03-05T15:00:00.000000+00:00')`,
+			},
+			want: map[string]int{"duplicate_lap_timestamps": 1},
+		},
+		{
+			name: "dos pilotos en la misma posición",
+			setup: []string{
+				`INSERT INTO PositionHistory (driver_number, session_key, position, date) VALUES
+				(1, 7953, 1, '2023-03-05T15:01:00.000000+00:00'),
+				(16, 7953, 1, '2023-03-05T15:01:00.000000+00:00')`,
+			},
+			want: map[string]int{"duplicate_positions": 1},
+		},
+		{
+			name: "vueltas fuera de la carrera o fuera de orden",
+			setup: []string{
+				`UPDATE Laps SET date_start = '2023-03-05T20:00:00.000000+00:00' WHERE driver_number = 1 AND session_key = 7953 AND lap_number = 2`,
+				`UPDATE Laps SET date_start = '2024-03-02T14:00:00.000000+00:00' WHERE driver_number = 4 AND session_key = 9472 AND lap_number = 1`,
+				`UPDATE Laps SET date_start = '2024-03-09T17:00:30.000000+00:00' WHERE driver_number = 16 AND session_key = 9480 AND lap_number = 3`,
+			},
+			want: map[string]int{"anomalous_lap_timestamps": 3},
+		},
+		{
+			name: "registros del piloto de reserva",
+			rejections: map[string][]openf1.Rejection{
+				"/v1/laps?session_key=9472": {
+					{Source: "/v1/laps?session_key=9472", Index: 3, Record: `{"driver_number":61}`, Reason: ingest.ReservedDriverReason},
+					{Source: "/v1/laps?session_key=9472", Index: 8, Record: `{"driver_number":61}`, Reason: ingest.ReservedDriverReason},
+				},
+				"/v1/position?session_key=9472": {
+					{Source: "/v1/position?session_key=9472", Index: 0, Record: `{"driver_number":61}`, Reason: ingest.ReservedDriverReason},
+				},
+			},
+			want: map[string]int{"reserved_driver_records": 2},
+		},
+		{
+			name: "registros rechazados",
+			rejections: map[string][]openf1.Rejection{
+				"/v1/laps?session_key=9480": {
+					{Source: "/v1/laps?session_key=9480", Index: 1, Record: `{"driver_number":1}`, Reason: "falta lap_number"},
+					{Source: "/v1/laps?session_key=9480", Index: 2, Record: `{"driver_number":61}`, Reason: ingest.ReservedDriverReason},
+				},
+			},
+			want: map[string]int{"rejected_records": 1, "reserved_driver_records": 1},
+		},
+	}
+
+	// Cada verificación tiene que tener un caso que la haga fallar
+	for _, c := range checks {
+		tested := false
+		for _, tc := range tests {
+			tested = tested || tc.want[c.name] > 0
+		}
+		if !tested {
+			t.Errorf("ningún caso hace fallar la verificación %s", c.name)
+		}
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db := storagetest.OpenSeeded(t)
+			for _, statement := range tc.setup {
+				if _, err := db.Exec(statement); err != nil {
+					t.Fatalf("%v\n%s", err, statement)
+				}
+			}
+			for source, rejected := range tc.rejections {
+				if err := ingest.SaveRejections(db, source, rejected); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			report, err := Run(context.Background(), db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Checks) != len(checks) {
+				t.Fatalf("el reporte tiene %d verificaciones, se esperaban %d", len(report.Checks), len(checks))
+			}
+
+			var critical, warnings int
+			for _, c := range report.Checks {
+				if c.Count != tc.want[c.Name] || len(c.Issues) != c.Count {
+					t.Errorf("%s: %d problema(s) (%+v), se esperaban %d", c.Name, c.Count, c.Issues, tc.want[c.Name])
+				}
+				if c.Severity == Critical {
+					critical += tc.want[c.Name]
+				} else {
+					warnings += tc.want[c.Name]
+				}
+			}
+			if report.Critical != critical || report.Warnings != warnings {
+				t.Errorf("Critical %d y Warnings %d, se esperaban %d y %d", report.Critical, report.Warnings, critical, warnings)
+			}
+
+			wantCode := 0
+			if critical > 0 {
+				wantCode = 1
+			}
+			if code := report.ExitCode(); code != wantCode {
+				t.Errorf("ExitCode = %d, se esperaba %d", code, wantCode)
+			}
+		})
+	}
+}
+
+// TestSeverities fija qué verificaciones son críticas: el resto son advertencias
+func TestSeverities(t *testing.T) {
+	critical := map[string]bool{"sessions_without_laps": true, "impossible_sector_sums": true}
+	for _, c := range checks {
+		if want := critical[c.name]; (c.severity == Critical) != want {
+			t.Errorf("%s tiene severidad %s", c.name, c.severity)
+		}
+	}
+}
+
+// TestReservedDriverSessionKey comprueba que reserved_driver_records tome la session_key
+// de la fuente
+func TestReservedDriverSessionKey(t *testing.T) {
+	db := storagetest.OpenSeeded(t)
+	source := "/v1/position?session_key=9480"
+	err := ingest.SaveRejections(db, source, []openf1.Rejection{
+		{Source: source, Index: 0, Record: `{"driver_number":61}`, Reason: ingest.ReservedDriverReason},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := reservedDriverRecords(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].SessionKey == nil || *issues[0].SessionKey != 9480 {
+		t.Errorf("reservedDriverRecords = %+v, se esperaba un problema de la carrera 9480", issues)
+	}
+}